@@ -0,0 +1,97 @@
+package accounts
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"example.com/mud/models"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/player"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPBKDF2SHA256(t *testing.T) {
+	t.Parallel()
+
+	// test vectors from RFC 7914 section 11
+	type tc struct {
+		password   string
+		salt       string
+		iterations int
+		keyLen     int
+		want       string
+	}
+
+	cases := []tc{
+		{
+			password:   "passwd",
+			salt:       "salt",
+			iterations: 1,
+			keyLen:     64,
+			want:       "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783",
+		},
+		{
+			password:   "Password",
+			salt:       "NaCl",
+			iterations: 80000,
+			keyLen:     64,
+			want:       "4ddcd8f60b98be21830cee5ef22701f9641a4418d04c0414aeff08876b34ab56a1d425a1225833549adb841b51c9b3176a272bdebba1d078478f62b397f33c8d",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.password, func(t *testing.T) {
+			t.Parallel()
+
+			got := pbkdf2SHA256([]byte(c.password), []byte(c.salt), c.iterations, c.keyLen)
+			require.Equal(t, c.want, hex.EncodeToString(got))
+		})
+	}
+}
+
+func TestCheckPassword(t *testing.T) {
+	t.Parallel()
+
+	hash, err := HashPassword("hunter2")
+	require.NoError(t, err)
+
+	require.True(t, CheckPassword(hash, "hunter2"))
+	require.False(t, CheckPassword(hash, "hunter3"))
+	require.False(t, CheckPassword("not a hash", "hunter2"))
+
+	// salts differ, so the same password never hashes the same twice
+	other, err := HashPassword("hunter2")
+	require.NoError(t, err)
+	require.NotEqual(t, hash, other)
+}
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewStore(t.TempDir())
+	require.NoError(t, err)
+
+	_, err = store.Load("Jack")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	account, err := store.Create("Jack", "hunter2")
+	require.NoError(t, err)
+
+	_, err = store.Create("jack", "other")
+	require.Error(t, err)
+
+	account.Player = &player.State{
+		Room: "LivingRoom",
+		Entity: &entities.EntityState{
+			Id:     "Player",
+			Name:   "Jack",
+			Fields: map[string]models.Value{"hp": models.VInt(7)},
+		},
+	}
+	require.NoError(t, store.Save(account))
+
+	loaded, err := store.Load("JACK")
+	require.NoError(t, err)
+	require.Equal(t, account, loaded)
+	require.True(t, CheckPassword(loaded.PasswordHash, "hunter2"))
+}
@@ -0,0 +1,106 @@
+package accounts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"example.com/mud/utils"
+	"example.com/mud/world/player"
+)
+
+var ErrNotFound = errors.New("account not found")
+
+type Account struct {
+	Name         string        `json:"name"`
+	PasswordHash string        `json:"passwordHash"`
+	Player       *player.State `json:"player,omitempty"`
+}
+
+// Store keeps accounts as one JSON file per account in a directory.
+type Store struct {
+	mu  sync.Mutex
+	dir string
+}
+
+func NewStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create account store: %w", err)
+	}
+
+	return &Store{dir: dir}, nil
+}
+
+// names are case insensitive, "Jack" and "jack" are the same account
+func (s *Store) path(name string) string {
+	return filepath.Join(s.dir, strings.ToLower(name)+".json")
+}
+
+func (s *Store) Load(name string) (*Account, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("load account '%s': %w", name, err)
+	}
+
+	var account Account
+	if err := json.Unmarshal(data, &account); err != nil {
+		return nil, fmt.Errorf("load account '%s': %w", name, err)
+	}
+
+	return &account, nil
+}
+
+// Create saves a new account, failing if the name is already taken.
+func (s *Store) Create(name, password string) (*Account, error) {
+	hash, err := HashPassword(password)
+	if err != nil {
+		return nil, fmt.Errorf("create account '%s': %w", name, err)
+	}
+
+	account := &Account{
+		Name:         name,
+		PasswordHash: hash,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := os.Stat(s.path(name)); err == nil {
+		return nil, fmt.Errorf("create account '%s': name is taken", name)
+	}
+
+	if err := s.write(account); err != nil {
+		return nil, fmt.Errorf("create account '%s': %w", name, err)
+	}
+
+	return account, nil
+}
+
+func (s *Store) Save(account *Account) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.write(account); err != nil {
+		return fmt.Errorf("save account '%s': %w", account.Name, err)
+	}
+
+	return nil
+}
+
+func (s *Store) write(account *Account) error {
+	data, err := json.MarshalIndent(account, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return utils.WriteFileAtomic(s.path(account.Name), data)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"example.com/mud/config"
+	"example.com/mud/dsl"
+)
+
+type checkReport struct {
+	Findings []dsl.Finding `json:"findings"`
+	Errors   int           `json:"errors"`
+	Warnings int           `json:"warnings"`
+}
+
+// runCheck lints the world definitions without starting the server, for
+// `mud check`. It returns the exit code: 1 if anything failed the check,
+// 2 if the check couldn't run at all.
+func runCheck(args []string, out io.Writer) int {
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	flags.SetOutput(out)
+	dataDir := flags.String("data", dataDirectory, "directory of definition files to check")
+	configPath := flags.String("config", "config.yaml", "config file naming the starting room")
+	format := flags.String("format", "text", `output format, "text" or "json"`)
+	strict := flags.Bool("strict", false, "fail on warnings as well as errors")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(out, "unknown format %q\n", *format)
+		return 2
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintf(out, "failed to load config: %v\n", err)
+		return 2
+	}
+
+	findings, err := dsl.Check(*dataDir, cfg.StartingRoom)
+	if err != nil {
+		fmt.Fprintf(out, "failed to check %s: %v\n", *dataDir, err)
+		return 2
+	}
+
+	report := checkReport{Findings: findings}
+	for _, f := range findings {
+		if f.Severity == dsl.SeverityError {
+			report.Errors++
+		} else {
+			report.Warnings++
+		}
+	}
+
+	if *format == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if report.Findings == nil {
+			report.Findings = []dsl.Finding{}
+		}
+		if err := enc.Encode(report); err != nil {
+			return 2
+		}
+	} else {
+		for _, f := range findings {
+			fmt.Fprintln(out, f)
+		}
+		fmt.Fprintf(out, "%d errors, %d warnings\n", report.Errors, report.Warnings)
+	}
+
+	if report.Errors > 0 || (*strict && report.Warnings > 0) {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"net"
+
+	"example.com/mud/accounts"
+	"example.com/mud/config"
+	"example.com/mud/models"
+	"example.com/mud/telnet"
+	"example.com/mud/utils"
+	"example.com/mud/world"
+)
+
+// telnetClient speaks line-based telnet, sending out-of-band data over GMCP.
+type telnetClient struct {
+	conn    *telnet.Conn
+	scanner *bufio.Scanner
+}
+
+func newTelnetClient(rawConn net.Conn) (*telnetClient, error) {
+	// negotiate and strip telnet sequences before anything reads lines
+	conn := telnet.NewConn(rawConn)
+	if err := conn.Negotiate(); err != nil {
+		return nil, err
+	}
+
+	return &telnetClient{
+		conn:    conn,
+		scanner: bufio.NewScanner(conn),
+	}, nil
+}
+
+func (c *telnetClient) ReadLine() (string, error) {
+	if !c.scanner.Scan() {
+		if err := c.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	return c.scanner.Text(), nil
+}
+
+func (c *telnetClient) Prompt(text string) error {
+	_, err := c.conn.Write([]byte(text))
+	return err
+}
+
+func (c *telnetClient) WriteLine(text string) error {
+	// wrap to the window size the client sent, if it did, before color codes
+	// and whether the text is preformatted are lost
+	text = telnet.Wrap(text, c.conn.Width())
+	if !c.conn.Color() {
+		text = utils.StripANSI(text)
+	}
+
+	// Use CRLF for telnet clients
+	_, err := c.conn.Write([]byte(text + "\r\n"))
+	return err
+}
+
+func (c *telnetClient) WriteData(msg models.OutOfBand) error {
+	// clients that refused GMCP only get prose
+	if !c.conn.GMCPEnabled() {
+		return nil
+	}
+	return c.conn.SendGMCP(msg.Package, msg.Data)
+}
+
+func (c *telnetClient) SetEcho(on bool) error {
+	return c.conn.SetEcho(on)
+}
+
+func (c *telnetClient) Close() error {
+	return c.conn.Close()
+}
+
+func handleTelnetConnection(rawConn net.Conn, gameWorld *world.World, store *accounts.Store, sessions *sessionTracker, cfg *config.Config) {
+	c, err := newTelnetClient(rawConn)
+	if err != nil {
+		rawConn.Close()
+		return
+	}
+
+	sessions.run(c, gameWorld, store, cfg)
+}
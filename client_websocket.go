@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"example.com/mud/accounts"
+	"example.com/mud/config"
+	"example.com/mud/models"
+	"example.com/mud/utils"
+	"example.com/mud/websocket"
+	"example.com/mud/world"
+)
+
+// websocket message formats, chosen with the "format" query parameter
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+// how ANSI codes are delivered, chosen with the "ansi" query parameter
+const (
+	ansiKeep  = "keep"
+	ansiStrip = "strip"
+	ansiHTML  = "html"
+)
+
+// envelope wraps every message when the json format is used, so clients can
+// tell prose apart from structured data.
+type envelope struct {
+	Type    string          `json:"type"`
+	Text    string          `json:"text,omitempty"`
+	Package string          `json:"package,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+	Echo    *bool           `json:"echo,omitempty"`
+}
+
+// websocketClient sends one websocket message per inbox message. Input is
+// expected one line per message.
+type websocketClient struct {
+	conn   *websocket.Conn
+	format string
+	ansi   string
+}
+
+// newWebsocketClient checks the options a browser asked for, so they can be
+// turned down before the connection is upgraded and given to the client.
+func newWebsocketClient(format, ansi string) (*websocketClient, error) {
+	switch format {
+	case "":
+		format = formatText
+	case formatText, formatJSON:
+	default:
+		return nil, fmt.Errorf("unknown format '%s'", format)
+	}
+
+	switch ansi {
+	case "":
+		ansi = ansiKeep
+	case ansiKeep, ansiStrip, ansiHTML:
+	default:
+		return nil, fmt.Errorf("unknown ansi mode '%s'", ansi)
+	}
+
+	return &websocketClient{
+		format: format,
+		ansi:   ansi,
+	}, nil
+}
+
+func (c *websocketClient) ReadLine() (string, error) {
+	msg, err := c.conn.ReadMessage()
+	if err != nil {
+		return "", err
+	}
+
+	if c.format == formatJSON {
+		var env envelope
+		if err := json.Unmarshal(msg, &env); err == nil {
+			return env.Text, nil
+		}
+	}
+
+	return string(msg), nil
+}
+
+func (c *websocketClient) Prompt(text string) error {
+	return c.writeText("prompt", text)
+}
+
+func (c *websocketClient) WriteLine(text string) error {
+	return c.writeText("text", text)
+}
+
+func (c *websocketClient) writeText(kind, text string) error {
+	switch c.ansi {
+	case ansiStrip:
+		text = utils.StripANSI(text)
+	case ansiHTML:
+		text = utils.ANSIToHTML(text)
+	}
+
+	if c.format != formatJSON {
+		return c.conn.WriteMessage([]byte(text))
+	}
+
+	return c.writeEnvelope(envelope{Type: kind, Text: text})
+}
+
+func (c *websocketClient) WriteData(msg models.OutOfBand) error {
+	// plain text clients only get prose
+	if c.format != formatJSON {
+		return nil
+	}
+
+	return c.writeEnvelope(envelope{Type: "data", Package: msg.Package, Data: msg.Data})
+}
+
+func (c *websocketClient) writeEnvelope(env envelope) error {
+	payload, err := json.Marshal(env)
+	if err != nil {
+		return fmt.Errorf("marshal envelope: %w", err)
+	}
+	return c.conn.WriteMessage(payload)
+}
+
+// SetEcho tells json clients to mask their input, plain text clients are on their own.
+func (c *websocketClient) SetEcho(on bool) error {
+	if c.format != formatJSON {
+		return nil
+	}
+
+	return c.writeEnvelope(envelope{Type: "echo", Echo: &on})
+}
+
+func (c *websocketClient) Close() error {
+	return c.conn.Close()
+}
+
+func websocketHandler(gameWorld *world.World, store *accounts.Store, sessions *sessionTracker, cfg *config.Config) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		// validate options before upgrading, so bad ones get a readable error
+		c, err := newWebsocketClient(query.Get("format"), query.Get("ansi"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		c.conn, err = websocket.Upgrade(w, r, cfg.AllowedOrigins)
+		if err != nil {
+			fmt.Println("Error upgrading connection:", err)
+			return
+		}
+
+		sessions.run(c, gameWorld, store, cfg)
+	})
+}
@@ -10,6 +10,36 @@ import (
 type Config struct {
 	StartingRoom    string `yaml:"startingRoom"`
 	PlayerRateLimit int    `yaml:"playerRateLimit"`
+	// maps show every room nearby, not only those the player has explored
+	RevealMap bool `yaml:"revealMap"`
+
+	// address for the websocket gateway, e.g. ":4080", left empty to disable
+	WebAddress string `yaml:"webAddress"`
+	// pages on other sites allowed to open websockets, e.g.
+	// "https://play.example.com", or "*" for any
+	AllowedOrigins []string `yaml:"allowedOrigins"`
+
+	// directory player accounts are saved to, left empty to disable accounts
+	AccountsDirectory string `yaml:"accountsDirectory"`
+
+	// names of players allowed to use admin commands
+	Admins []string `yaml:"admins"`
+
+	// file the world is saved to and restored from, left empty to disable saving
+	SnapshotFile string `yaml:"snapshotFile"`
+	// seconds between automatic saves, 0 to only save on demand
+	SnapshotInterval int `yaml:"snapshotInterval"`
+	// what restored jobs that came due while the server was down do, "catchup"
+	// to run every missed run on boot, or "skip" to drop them
+	MissedJobs string `yaml:"missedJobs"`
+
+	// reload the world whenever a file in data/ changes
+	WatchData bool `yaml:"watchData"`
+
+	// seconds players are warned for before the server shuts down
+	ShutdownCountdown int `yaml:"shutdownCountdown"`
+	// warning broadcast during the countdown, "{seconds}" is replaced with the time left
+	ShutdownMessage string `yaml:"shutdownMessage"`
 }
 
 func Load(path string) (*Config, error) {
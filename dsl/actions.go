@@ -7,6 +7,8 @@ import (
 	"example.com/mud/models"
 	"example.com/mud/world/entities"
 	"example.com/mud/world/entities/actions"
+	"example.com/mud/world/entities/expressions"
+	"github.com/alecthomas/participle/v2/lexer"
 )
 
 type ActionDef struct {
@@ -16,8 +18,12 @@ type ActionDef struct {
 	Move                    *MoveAction              `parser:"| 'move' @@"`
 	SetField                *SetFieldAction          `parser:"| 'set' @@"`
 	DestroyAction           *DestroyAction           `parser:"| 'destroy' @@"`
+	CancelAction            *CancelAction            `parser:"| 'cancel' @@"`
+	PerformAction           *PerformAction           `parser:"| 'perform' @@"`
+	GoAction                *GoAction                `parser:"| 'go' @@"`
 	ScheduleOnceAction      *ScheduleOnceAction      `parser:"| @@"`
 	ScheduleRepeatingAction *ScheduleRepeatingAction `parser:"| @@"`
+	RevealExitAction        *RevealExitAction        `parser:"| @@"`
 	RevealChildrenAction    *RevealChildrenAction    `parser:"| @@"`
 	ConditionalAction       *ConditionalAction       `parser:"| @@"`
 }
@@ -44,9 +50,20 @@ type MoveAction struct {
 }
 
 type SetFieldAction struct {
-	Role  string     `parser:"@Ident"`
-	Field string     `parser:"'.' @Ident"`
-	Expr  Expression `parser:"'to' @@"`
+	Role  string      `parser:"@Ident"`
+	Field string      `parser:"'.' @Ident"`
+	Key   *Expression `parser:"( '[' @@ ']' )?"`
+	Expr  Expression  `parser:"'to' @@"`
+}
+
+type GoAction struct {
+	Role      string `parser:"@Ident"`
+	Direction string `parser:"@(Ident | String)"`
+}
+
+type RevealExitAction struct {
+	Set       string `parser:"@('reveal' | 'hide')"`
+	Direction string `parser:"'exit' @String"`
 }
 
 type RevealChildrenAction struct {
@@ -56,14 +73,20 @@ type RevealChildrenAction struct {
 }
 
 type ScheduleOnceAction struct {
+	Pos lexer.Position
+
 	ExprIn *Expression `parser:"'in' @@"`
 	Units  string      `parser:"@( 'second' | 'seconds' | 'minute' | 'minutes' )"`
+	Label  string      `parser:"[ 'as' @String ]"`
 	Then   *ThenBlock  `parser:"@@"`
 }
 
 type ScheduleRepeatingAction struct {
+	Pos lexer.Position
+
 	ExprIn *Expression `parser:"'repeat' 'every' @@"`
 	Units  string      `parser:"@( 'second' | 'seconds' | 'minute' | 'minutes' )"`
+	Label  string      `parser:"[ 'as' @String ]"`
 	While  *IfDef      `parser:"'while' @@"`
 }
 
@@ -86,6 +109,16 @@ type DestroyAction struct {
 	Role string `parser:"@Ident"`
 }
 
+type PerformAction struct {
+	Role string `parser:"@Ident"`
+	Line string `parser:"@String"`
+}
+
+type CancelAction struct {
+	Role  string `parser:"@Ident"`
+	Label string `parser:"[ @String ]"`
+}
+
 func (def *ActionDef) Build() (entities.Action, error) {
 	switch {
 	case def.Print != nil:
@@ -100,6 +133,14 @@ func (def *ActionDef) Build() (entities.Action, error) {
 		return def.SetField.Build()
 	case def.DestroyAction != nil:
 		return def.DestroyAction.Build()
+	case def.CancelAction != nil:
+		return def.CancelAction.Build()
+	case def.PerformAction != nil:
+		return def.PerformAction.Build()
+	case def.GoAction != nil:
+		return def.GoAction.Build()
+	case def.RevealExitAction != nil:
+		return def.RevealExitAction.Build()
 	case def.RevealChildrenAction != nil:
 		return def.RevealChildrenAction.Build()
 	case def.ConditionalAction != nil:
@@ -183,9 +224,18 @@ func (def *SetFieldAction) Build() (entities.Action, error) {
 		return nil, fmt.Errorf("expression set field action: %w", err)
 	}
 
+	var key expressions.Expression
+	if def.Key != nil {
+		key, err = def.Key.Build()
+		if err != nil {
+			return nil, fmt.Errorf("key set field action: %w", err)
+		}
+	}
+
 	return &actions.SetField{
 		Role:       role,
 		Field:      def.Field,
+		Key:        key,
 		Expression: expression,
 	}, nil
 }
@@ -201,6 +251,49 @@ func (def *DestroyAction) Build() (entities.Action, error) {
 	}, nil
 }
 
+func (def *PerformAction) Build() (entities.Action, error) {
+	role, err := entities.ParseEventRole(def.Role)
+	if err != nil {
+		return nil, fmt.Errorf("event perform action: %w", err)
+	}
+
+	return &actions.Perform{
+		Role: role,
+		Line: def.Line,
+	}, nil
+}
+
+func (def *GoAction) Build() (entities.Action, error) {
+	role, err := entities.ParseEventRole(def.Role)
+	if err != nil {
+		return nil, fmt.Errorf("event go action: %w", err)
+	}
+
+	return &actions.Go{
+		Role:      role,
+		Direction: def.Direction,
+	}, nil
+}
+
+func (def *CancelAction) Build() (entities.Action, error) {
+	role, err := entities.ParseEventRole(def.Role)
+	if err != nil {
+		return nil, fmt.Errorf("event cancel action: %w", err)
+	}
+
+	return &actions.Cancel{
+		Role:  role,
+		Label: def.Label,
+	}, nil
+}
+
+func (def *RevealExitAction) Build() (entities.Action, error) {
+	return &actions.RevealExit{
+		Direction: def.Direction,
+		Reveal:    def.Set == "reveal",
+	}, nil
+}
+
 func (def *RevealChildrenAction) Build() (entities.Action, error) {
 	role, err := entities.ParseEventRole(def.Role)
 	if err != nil {
@@ -240,9 +333,15 @@ func (def *ScheduleOnceAction) Build() (entities.Action, error) {
 		return nil, fmt.Errorf("could not build schedule once then actions: %w", err)
 	}
 
+	label := def.Label
+	if label == "" {
+		label = fmt.Sprintf("in %d %s", value.I, def.Units)
+	}
+
 	return &actions.ScheduleOnce{
 		Nanoseconds: time.Duration(value.I) * unitMultiplier,
 		Actions:     then,
+		Label:       label,
 	}, nil
 }
 
@@ -272,9 +371,15 @@ func (def *ScheduleRepeatingAction) Build() (entities.Action, error) {
 		return nil, fmt.Errorf("could not build rule for schedule repeating action: %w", err)
 	}
 
+	label := def.Label
+	if label == "" {
+		label = fmt.Sprintf("every %d %s", value.I, def.Units)
+	}
+
 	return &actions.ScheduleRepeating{
 		Nanoseconds: time.Duration(value.I) * unitMultiplier,
 		Rule:        rule,
+		Label:       label,
 	}, nil
 }
 
@@ -25,22 +25,29 @@ type DSL struct {
 }
 
 type TopLevel struct {
-	Entity  *EntityDef  `parser:"'entity' @@"`
-	Trait   *TraitDef   `parser:"| 'trait' @@"`
-	Command *CommandDef `parser:"| 'command' @@"`
+	Entity    *EntityDef    `parser:"'entity' @@"`
+	Trait     *TraitDef     `parser:"| 'trait' @@"`
+	Command   *CommandDef   `parser:"| 'command' @@"`
+	Direction *DirectionDef `parser:"| 'direction' @@"`
 }
 
 type EntityDef struct {
+	Pos lexer.Position
+
 	Name   string         `parser:"@Ident"`
 	Blocks []*EntityBlock `parser:"'{' { @@ } '}'"`
 }
 
 type TraitDef struct {
+	Pos lexer.Position
+
 	Name   string         `parser:"@Ident"`
 	Blocks []*EntityBlock `parser:"'{' { @@ } '}'"`
 }
 
 type EntityBlock struct {
+	Pos lexer.Position
+
 	Component *ComponentDef        `parser:"  'component' @@"`
 	Trait     *TraitInheritanceDef `parser:"| 'trait' @@"`
 	Reaction  *ReactionDef         `parser:"| 'react' @@"`
@@ -48,17 +55,15 @@ type EntityBlock struct {
 }
 
 type TraitInheritanceDef struct {
+	Pos lexer.Position
+
 	Name   string      `parser:"@Ident"`
 	Fields []*FieldDef `parser:"( '{' { @@ } '}' )?"`
 }
 
 type FieldDef struct {
+	Pos lexer.Position
+
 	Key   string      `parser:"@Ident 'is'"`
 	Value *Expression `parser:"@@"`
-	Pairs []KV        `parser:"| '{' @@ { ',' @@ } '}'"`
-}
-
-type KV struct {
-	Key   string `parser:"@String"`
-	Value string `parser:"':' @String"`
 }
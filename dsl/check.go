@@ -0,0 +1,354 @@
+package dsl
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"example.com/mud/parser/commands"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/components"
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+type Severity string
+
+const (
+	// SeverityError is a mistake that stops the world from loading, or leaves
+	// part of it broken
+	SeverityError Severity = "error"
+	// SeverityWarning is something that works, but probably isn't what the
+	// builder wanted
+	SeverityWarning Severity = "warning"
+)
+
+// names of the checks, so findings can be filtered on
+const (
+	CheckSyntax          = "syntax"
+	CheckCompile         = "compile"
+	CheckUnknownChild    = "unknown-child"
+	CheckUnknownTrait    = "unknown-trait"
+	CheckStartingRoom    = "starting-room"
+	CheckExit            = "exit"
+	CheckExitLink        = "exit-link"
+	CheckUnknownVerb     = "unknown-verb"
+	CheckUnusedCommand   = "unused-command"
+	CheckUnreachableRoom = "unreachable-room"
+	CheckDuplicateAlias  = "duplicate-alias"
+)
+
+// Finding is a single problem found by Check.
+type Finding struct {
+	File     string   `json:"file"`
+	Line     int      `json:"line"`
+	Column   int      `json:"column"`
+	Severity Severity `json:"severity"`
+	Check    string   `json:"check"`
+	Message  string   `json:"message"`
+}
+
+func (f Finding) String() string {
+	pos := lexer.Position{Filename: f.File, Line: f.Line, Column: f.Column}
+	return fmt.Sprintf("%s: %s: %s [%s]", pos, f.Severity, f.Message, f.Check)
+}
+
+func newFinding(pos lexer.Position, severity Severity, check string, message string) Finding {
+	return Finding{
+		File:     pos.Filename,
+		Line:     pos.Line,
+		Column:   pos.Column,
+		Severity: severity,
+		Check:    check,
+		Message:  message,
+	}
+}
+
+// Check loads every definition under directoryName and reports problems with
+// them, without starting a world. Only failing to read the directory is
+// returned as an error, everything else is a finding.
+func Check(directoryName string, startingRoom string) ([]Finding, error) {
+	ast, err := parseDirectory(directoryName, false)
+	if err != nil {
+		var list ErrorList
+		if !errors.As(err, &list) {
+			return nil, err
+		}
+
+		// nothing else can be checked without a syntax tree
+		return findingsFromErrors(list, CheckSyntax), nil
+	}
+
+	c := &checker{}
+
+	defs := collectDefs(ast.Declarations, &ErrorList{})
+	c.checkVerbs(defs)
+
+	// rooms which compiled are checked even when others didn't
+	prototypes, cmds, errs := compile(ast)
+	c.findings = append(c.findings, findingsFromErrors(errs, CheckCompile)...)
+
+	opposites, _ := commands.OppositeDirections(cmds)
+	c.checkRooms(defs, prototypes, opposites, startingRoom)
+
+	sort.SliceStable(c.findings, func(i, j int) bool {
+		a, b := c.findings[i], c.findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+		return a.Message < b.Message
+	})
+
+	return c.findings, nil
+}
+
+func findingsFromErrors(list ErrorList, check string) []Finding {
+	findings := make([]Finding, 0, len(list))
+	for _, e := range list {
+		check := check
+		if errors.Is(e.Err, ErrUnknownPrototype) {
+			check = CheckUnknownChild
+		} else if errors.Is(e.Err, ErrUnknownTrait) {
+			check = CheckUnknownTrait
+		}
+
+		findings = append(findings, newFinding(e.Pos, SeverityError, check, e.Err.Error()))
+	}
+	return findings
+}
+
+type checker struct {
+	findings []Finding
+}
+
+func (c *checker) report(pos lexer.Position, severity Severity, check string, format string, args ...any) {
+	c.findings = append(c.findings, newFinding(pos, severity, check, fmt.Sprintf(format, args...)))
+}
+
+// reactions only ever run for commands or events sent by the world, so every
+// verb reacted to should be one, and every command should have something
+// reacting to it
+func (c *checker) checkVerbs(defs *collectedDefs) {
+	commands := make(map[string]CommandDef, len(defs.commandsById))
+	for _, cd := range defs.commandsById {
+		commands[strings.ToLower(cd.Name)] = cd
+	}
+
+	reacted := map[string]struct{}{}
+	checkBlocks := func(blocks []*EntityBlock) {
+		for _, block := range blocks {
+			if block.Reaction == nil {
+				continue
+			}
+
+			for _, verb := range block.Reaction.Commands {
+				reacted[verb] = struct{}{}
+				if _, ok := commands[verb]; !ok && !entities.IsBuiltInEvent(verb) {
+					c.report(block.Reaction.Pos, SeverityWarning, CheckUnknownVerb,
+						"reaction to '%s', but no command defines it", verb)
+				}
+			}
+		}
+	}
+
+	for _, ed := range defs.entitiesById {
+		checkBlocks(ed.Blocks)
+	}
+	for _, td := range defs.traitsById {
+		checkBlocks(td.Blocks)
+	}
+
+	// doors answer to these without any reactions
+	if definesDoors(defs) {
+		for _, verb := range []string{"open", "close", "lock", "unlock"} {
+			reacted[verb] = struct{}{}
+		}
+	}
+
+	for name, cd := range commands {
+		if _, ok := reacted[name]; !ok {
+			c.report(cd.Pos, SeverityWarning, CheckUnusedCommand,
+				"command '%s' has no reactions, so it will never do anything", cd.Name)
+		}
+	}
+}
+
+func definesDoors(defs *collectedDefs) bool {
+	for _, ed := range defs.entitiesById {
+		for _, block := range ed.Blocks {
+			if block.Component == nil {
+				continue
+			}
+			for _, exit := range block.Component.Exits {
+				for _, f := range exit.Fields {
+					switch f.Key {
+					case "door", "closed", "locked", "key":
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// defined reports whether an entity is defined, whether or not it compiled
+func (d *collectedDefs) defined(id string) bool {
+	_, ok := d.entitiesById[id]
+	return ok
+}
+
+func (c *checker) checkRooms(defs *collectedDefs, prototypes map[string]*entities.Entity, opposites map[string]string, startingRoom string) {
+	rooms := make(map[string]*components.Room)
+	for id, e := range prototypes {
+		if rm, ok := entities.GetComponent[*components.Room](e); ok {
+			rooms[id] = rm
+		}
+	}
+
+	for id, rm := range rooms {
+		for direction, exit := range rm.Exits {
+			pos := exitPosition(defs.entitiesById[id], direction)
+			target := exit.RoomId
+
+			e, ok := prototypes[target]
+			if !ok && defs.defined(target) {
+				// it failed to compile, which is reported already
+				continue
+			}
+			if !ok {
+				c.report(pos, SeverityError, CheckExit,
+					"exit '%s' of '%s' leads to '%s', which doesn't exist", direction, id, target)
+				continue
+			}
+			if _, ok := entities.GetComponent[*components.Room](e); !ok {
+				c.report(pos, SeverityError, CheckExit,
+					"exit '%s' of '%s' leads to '%s', which isn't a room", direction, id, target)
+				continue
+			}
+
+			// going out and straight back should end up where you started,
+			// unless the passages are meant to be twisty
+			back, ok := reverseExitOf(exit, prototypes, opposites)
+			if !ok || rm.Links == components.LinksNone {
+				continue
+			}
+			if reverse, ok := rooms[target].Exits[back]; ok && reverse.RoomId != id {
+				c.report(pos, SeverityWarning, CheckExitLink,
+					"exit '%s' of '%s' leads to '%s', but its '%s' exit leads to '%s'", direction, id, target, back, reverse.RoomId)
+			}
+		}
+
+		c.checkAliases(defs.entitiesById[id], rm)
+	}
+
+	if startingRoom == "" {
+		return
+	}
+	if _, ok := prototypes[startingRoom]; !ok && defs.defined(startingRoom) {
+		return
+	}
+	if _, ok := rooms[startingRoom]; !ok {
+		c.report(lexer.Position{}, SeverityError, CheckStartingRoom,
+			"starting room '%s' isn't a room in the world", startingRoom)
+		return
+	}
+
+	// walk exits out from the starting room, anything left over can't be walked to
+	visited := map[string]struct{}{startingRoom: {}}
+	queue := []string{startingRoom}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		// hidden and locked exits count, something may open them
+		for _, exit := range rooms[id].Exits {
+			target := exit.RoomId
+			if _, ok := prototypes[target]; !ok && defs.defined(target) {
+				// where a room which failed to compile leads is anyone's
+				// guess, so nothing can be called unreachable
+				return
+			}
+			if _, ok := rooms[target]; !ok {
+				continue
+			}
+			if _, ok := visited[target]; ok {
+				continue
+			}
+			visited[target] = struct{}{}
+			queue = append(queue, target)
+		}
+	}
+
+	for id := range rooms {
+		if _, ok := visited[id]; !ok {
+			c.report(defs.entitiesById[id].Pos, SeverityWarning, CheckUnreachableRoom,
+				"room '%s' can't be reached from the starting room '%s'", id, startingRoom)
+		}
+	}
+}
+
+// entities sharing an alias in the same room always make players choose
+// between them
+func (c *checker) checkAliases(def EntityDef, rm *components.Room) {
+	owners := map[string][]string{}
+	for _, child := range rm.GetChildren().GetChildren() {
+		seen := map[string]struct{}{}
+		for _, alias := range child.Aliases {
+			alias = strings.ToLower(alias)
+			if _, ok := seen[alias]; ok {
+				continue
+			}
+			seen[alias] = struct{}{}
+			owners[alias] = append(owners[alias], child.Id)
+		}
+	}
+
+	for alias, ids := range owners {
+		if len(ids) > 1 {
+			c.report(childrenPosition(def), SeverityWarning, CheckDuplicateAlias,
+				"alias '%s' is shared by %s in room '%s'", alias, strings.Join(ids, ", "), def.Name)
+		}
+	}
+}
+
+// where the exit in direction is defined, by its own block, or else the
+// room's exits field
+func exitPosition(def EntityDef, direction string) lexer.Position {
+	for _, block := range def.Blocks {
+		if block.Component == nil || block.Component.Name != "Room" {
+			continue
+		}
+		for _, exit := range block.Component.Exits {
+			if commands.NormalizeExitName(exit.Direction) == direction {
+				return exit.Pos
+			}
+		}
+	}
+	return roomFieldPosition(def, "exits")
+}
+
+func childrenPosition(def EntityDef) lexer.Position {
+	return roomFieldPosition(def, "children")
+}
+
+func roomFieldPosition(def EntityDef, key string) lexer.Position {
+	for _, block := range def.Blocks {
+		if block.Component == nil || block.Component.Name != "Room" {
+			continue
+		}
+		for _, f := range block.Component.Fields {
+			if f.Key == key {
+				return f.Pos
+			}
+		}
+		return block.Component.Pos
+	}
+	return def.Pos
+}
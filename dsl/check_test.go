@@ -0,0 +1,271 @@
+// dsl/check_test.go
+package dsl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	const rooms = `
+entity Hall {
+  name is "Hall"
+  description is "A hall."
+  aliases is ["hall"]
+  component Room {
+    exits is { "north": "Kitchen" }
+  }
+}
+
+entity Kitchen {
+  name is "Kitchen"
+  description is "A kitchen."
+  aliases is ["kitchen"]
+  component Room {
+    exits is { "south": "Hall" }
+  }
+}
+`
+
+	type want struct {
+		check    string
+		severity Severity
+		line     int
+	}
+
+	type tc struct {
+		name  string
+		files map[string]string
+		want  []want
+	}
+
+	cases := []tc{
+		{
+			name:  "clean world",
+			files: map[string]string{"rooms.mud": rooms},
+			want:  nil,
+		},
+		{
+			name: "syntax error",
+			files: map[string]string{
+				"rooms.mud": rooms,
+				"bad.mud":   "entity Lamp {\n  name is\n}\n",
+			},
+			want: []want{{CheckSyntax, SeverityError, 3}},
+		},
+		{
+			name: "exits to missing entities and non-rooms",
+			files: map[string]string{
+				"rooms.mud": rooms,
+				"cellar.mud": `
+entity Cellar {
+  name is "Cellar"
+  description is "A cellar."
+  aliases is ["cellar"]
+  component Room {
+    exits is { "up": "Attic", "down": "Barrel" }
+  }
+}
+
+entity Barrel {
+  name is "Barrel"
+  description is "A barrel."
+  aliases is ["barrel"]
+}
+`,
+			},
+			want: []want{
+				{CheckUnreachableRoom, SeverityWarning, 2},
+				{CheckExit, SeverityError, 7},
+				{CheckExit, SeverityError, 7},
+			},
+		},
+		{
+			name: "exits leading back somewhere else",
+			files: map[string]string{
+				"rooms.mud": rooms,
+				"pantry.mud": `
+entity Pantry {
+  name is "Pantry"
+  description is "A pantry."
+  aliases is ["pantry"]
+  component Room {
+    exits is { "north": "Kitchen" }
+  }
+}
+
+entity Maze {
+  name is "Maze"
+  description is "A twisty maze."
+  aliases is ["maze"]
+  component Room {
+    links is "none"
+    exits is { "north": "Kitchen" }
+  }
+}
+`,
+			},
+			want: []want{
+				{CheckUnreachableRoom, SeverityWarning, 2},
+				{CheckExitLink, SeverityWarning, 7},
+				{CheckUnreachableRoom, SeverityWarning, 11},
+			},
+		},
+		{
+			name: "exits linked back automatically",
+			files: map[string]string{
+				"rooms.mud": rooms,
+				"garden.mud": `
+entity Garden {
+  name is "Garden"
+  description is "A garden."
+  aliases is ["garden"]
+  component Room {
+    links is "auto"
+    exits is { "east": "Hall" }
+  }
+}
+`,
+			},
+			want: nil,
+		},
+		{
+			name: "unknown child and trait",
+			files: map[string]string{
+				"rooms.mud": rooms,
+				"things.mud": `
+entity Box {
+  name is "Box"
+  description is "A box."
+  aliases is ["box"]
+  trait Sturdy
+}
+
+entity Shelf {
+  name is "Shelf"
+  description is "A shelf."
+  aliases is ["shelf"]
+  component Container {
+    children is ["Ghost"]
+  }
+}
+`,
+			},
+			want: []want{
+				{CheckUnknownTrait, SeverityError, 6},
+				{CheckUnknownChild, SeverityError, 9},
+			},
+		},
+		{
+			name: "rooms are checked alongside compile errors",
+			files: map[string]string{
+				"rooms.mud": rooms,
+				"cellar.mud": `
+entity Cellar {
+  name is "Cellar"
+  description is "A cellar."
+  aliases is ["cellar"]
+  component Room {
+    exits is { "up": "Attic", "east": "Crypt" }
+  }
+}
+
+entity Crypt {
+  name is "Crypt"
+  description is "A crypt."
+  aliases is ["crypt"]
+  trait Haunted
+  component Room {
+    exits is { "west": "Cellar" }
+  }
+}
+`,
+			},
+			want: []want{
+				{CheckExit, SeverityError, 7},
+				{CheckUnknownTrait, SeverityError, 15},
+				{CheckUnreachableRoom, SeverityWarning, 2},
+			},
+		},
+		{
+			name: "verbs without commands and commands without reactions",
+			files: map[string]string{
+				"rooms.mud": rooms,
+				"verbs.mud": `
+command Wave {
+  aliases is ["wave"]
+  pattern { syntax is "wave {target}" }
+}
+
+trait Danceable {
+  react dance {
+    then { print source "You dance." }
+  }
+}
+`,
+			},
+			want: []want{
+				{CheckUnusedCommand, SeverityWarning, 2},
+				{CheckUnknownVerb, SeverityWarning, 8},
+			},
+		},
+		{
+			name: "duplicate aliases in a room",
+			files: map[string]string{
+				"rooms.mud": `
+entity Hall {
+  name is "Hall"
+  description is "A hall."
+  aliases is ["hall"]
+  component Room {
+    children is ["Lamp", "Torch"]
+  }
+}
+
+entity Lamp {
+  name is "Lamp"
+  description is "A lamp."
+  aliases is ["lamp", "light"]
+}
+
+entity Torch {
+  name is "Torch"
+  description is "A torch."
+  aliases is ["torch", "Light"]
+}
+`,
+			},
+			want: []want{{CheckDuplicateAlias, SeverityWarning, 7}},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			for name, content := range c.files {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+			}
+
+			findings, err := Check(dir, "Hall")
+			require.NoError(t, err)
+
+			got := make([]want, 0, len(findings))
+			for _, f := range findings {
+				got = append(got, want{f.Check, f.Severity, f.Line})
+			}
+			if c.want == nil {
+				require.Empty(t, got)
+			} else {
+				require.ElementsMatch(t, c.want, got)
+			}
+		})
+	}
+}
@@ -5,9 +5,12 @@ import (
 	"strings"
 
 	"example.com/mud/models"
+	"github.com/alecthomas/participle/v2/lexer"
 )
 
 type CommandDef struct {
+	Pos lexer.Position
+
 	Name   string          `parser:"@Ident"`
 	Blocks []*CommandBlock `parser:"'{' { @@ } '}'"`
 }
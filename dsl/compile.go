@@ -2,16 +2,22 @@ package dsl
 
 import (
 	"fmt"
+	"strings"
 
 	"example.com/mud/models"
+	"example.com/mud/parser/commands"
 	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/actions"
 	"example.com/mud/world/entities/components"
+	"github.com/alecthomas/participle/v2/lexer"
 )
 
 type collectedDefs struct {
 	entitiesById map[string]EntityDef
 	traitsById   map[string]TraitDef
 	commandsById map[string]CommandDef
+
+	directionsById map[string]DirectionDef
 }
 
 type ChildrenPlan map[string]map[entities.ComponentType][]string
@@ -39,70 +45,106 @@ type entityPrototypes struct {
 	visiting       map[string]struct{}
 }
 
+// Compile builds entities and commands from the parsed definitions. Errors
+// are collected into an ErrorList rather than stopping at the first one.
 func Compile(ast *DSL) (map[string]*entities.Entity, []*models.CommandDefinition, error) {
 	if ast == nil {
 		return nil, nil, fmt.Errorf("nil DSL")
 	}
 
-	collectedDefs, err := collectDefs(ast.Declarations)
-	if err != nil {
-		return nil, nil, fmt.Errorf("could not collect top level declarations: %w", err)
+	entitiesById, cmds, errs := compile(ast)
+	if err := errs.Err(); err != nil {
+		return nil, nil, err
 	}
 
-	prototypes, err := collectedDefs.collectPrototypes()
-	if err != nil {
-		return nil, nil, fmt.Errorf("could not collect prototype entities: %w", err)
-	}
+	return entitiesById, cmds, nil
+}
 
-	entitiesById, err := prototypes.instantiatePrototypes()
-	if err != nil {
-		return nil, nil, fmt.Errorf("could not instantiate prototype entities: %w", err)
-	}
+// compile builds everything it can, returning what compiled along with the
+// errors for what didn't
+func compile(ast *DSL) (map[string]*entities.Entity, []*models.CommandDefinition, ErrorList) {
+	var errs ErrorList
+
+	collectedDefs := collectDefs(ast.Declarations, &errs)
+	prototypes := collectedDefs.collectPrototypes(&errs)
+	entitiesById := prototypes.instantiatePrototypes(&errs)
 
-	commands := make([]*models.CommandDefinition, 0, len(collectedDefs.commandsById))
+	cmds := make([]*models.CommandDefinition, 0, len(collectedDefs.commandsById))
 	for _, c := range collectedDefs.commandsById {
 		cd, err := c.Build()
 		if err != nil {
-			return nil, nil, fmt.Errorf("could not instantiate command '%s': %w", c.Name, err)
+			errs.Add(c.Pos, fmt.Errorf("could not instantiate command '%s': %w", c.Name, err))
+			continue
+		}
+
+		cmds = append(cmds, cd)
+	}
+
+	// directions are registered along with the commands
+	for _, d := range collectedDefs.directionsById {
+		cd, err := d.Build()
+		if err != nil {
+			errs.Add(d.Pos, fmt.Errorf("could not instantiate direction '%s': %w", d.Name, err))
+			continue
 		}
 
-		commands = append(commands, cd)
+		cmds = append(cmds, cd)
 	}
 
-	return entitiesById, commands, nil
+	// rooms may have the exits leading back to them filled in
+	if opposites, err := commands.OppositeDirections(cmds); err != nil {
+		errs.Add(lexer.Position{}, err)
+	} else {
+		linkExits(entitiesById, opposites)
+	}
+
+	return entitiesById, cmds, errs
 }
 
-// collect entity, command and trait definitions
-func collectDefs(decls []*TopLevel) (*collectedDefs, error) {
+// collect entity, command, direction and trait definitions
+func collectDefs(decls []*TopLevel, errs *ErrorList) *collectedDefs {
 	entitiesById := make(map[string]EntityDef, len(decls))
 	commandsById := make(map[string]CommandDef, len(decls))
 	traitsById := make(map[string]TraitDef, len(decls))
+	directionsById := make(map[string]DirectionDef, len(decls))
 
 	for _, declaration := range decls {
 		if declaration == nil {
-			return nil, fmt.Errorf("declaration at top level is nil")
+			errs.Add(lexer.Position{}, fmt.Errorf("declaration at top level is nil"))
+			continue
 		}
 
 		if ed := declaration.Entity; ed != nil {
-			if _, exists := entitiesById[ed.Name]; exists {
-				return nil, fmt.Errorf("duplicate entity %s", ed.Name)
+			if existing, exists := entitiesById[ed.Name]; exists {
+				errs.Add(ed.Pos, fmt.Errorf("duplicate entity %s, first defined at %s", ed.Name, existing.Pos))
+				continue
 			}
 
 			entitiesById[ed.Name] = *ed
 		} else if td := declaration.Trait; td != nil {
-			if _, exists := entitiesById[td.Name]; exists {
-				return nil, fmt.Errorf("duplicate trait %s", td.Name)
+			if existing, exists := traitsById[td.Name]; exists {
+				errs.Add(td.Pos, fmt.Errorf("duplicate trait %s, first defined at %s", td.Name, existing.Pos))
+				continue
 			}
 
 			traitsById[declaration.Trait.Name] = *declaration.Trait
 		} else if ec := declaration.Command; ec != nil {
-			if _, exists := commandsById[ec.Name]; exists {
-				return nil, fmt.Errorf("duplicate command %s", ec.Name)
+			if existing, exists := commandsById[ec.Name]; exists {
+				errs.Add(ec.Pos, fmt.Errorf("duplicate command %s, first defined at %s", ec.Name, existing.Pos))
+				continue
 			}
 
 			commandsById[declaration.Command.Name] = *declaration.Command
+		} else if dd := declaration.Direction; dd != nil {
+			name := strings.ToLower(dd.Name)
+			if existing, exists := directionsById[name]; exists {
+				errs.Add(dd.Pos, fmt.Errorf("duplicate direction %s, first defined at %s", dd.Name, existing.Pos))
+				continue
+			}
+
+			directionsById[name] = *dd
 		} else {
-			return nil, fmt.Errorf("declaration at top level is empty")
+			errs.Add(lexer.Position{}, fmt.Errorf("declaration at top level is empty"))
 		}
 	}
 
@@ -110,11 +152,13 @@ func collectDefs(decls []*TopLevel) (*collectedDefs, error) {
 		entitiesById: entitiesById,
 		traitsById:   traitsById,
 		commandsById: commandsById,
-	}, nil
+
+		directionsById: directionsById,
+	}
 }
 
 // expand traits in each entity definition
-func (c *collectedDefs) collectPrototypes() (*entityPrototypes, error) {
+func (c *collectedDefs) collectPrototypes(errs *ErrorList) *entityPrototypes {
 	ep := &entityPrototypes{
 		prototypesById: map[string]*entityPrototype{},
 		traitsById:     c.traitsById,
@@ -125,9 +169,10 @@ func (c *collectedDefs) collectPrototypes() (*entityPrototypes, error) {
 	// build prototypes of each entity and put them in name->builtEntity map
 	for name, ed := range c.entitiesById {
 		// build prototype and populate pending children
-		prototypeEntity, err := ep.buildPrototype(name, ed.Blocks)
+		prototypeEntity, err := ep.buildPrototype(name, ed.Pos, ed.Blocks)
 		if err != nil {
-			return nil, fmt.Errorf("build %s: %w", name, err)
+			errs.Add(ed.Pos, fmt.Errorf("build %s: %w", name, err))
+			continue
 		}
 		ep.prototypesById[name] = &entityPrototype{
 			id:  name,
@@ -136,13 +181,13 @@ func (c *collectedDefs) collectPrototypes() (*entityPrototypes, error) {
 		}
 	}
 
-	return ep, nil
+	return ep
 }
 
 // create prototype entity with components. collect child prototype names into the sidecar for later.
-func (ep *entityPrototypes) buildPrototype(id string, blocks []*EntityBlock) (*entities.Entity, error) {
+func (ep *entityPrototypes) buildPrototype(id string, pos lexer.Position, blocks []*EntityBlock) (*entities.Entity, error) {
 
-	loweredEntity, err := ep.lowerEntity(id, blocks)
+	loweredEntity, err := ep.lowerEntity(id, pos, blocks)
 	if err != nil {
 		return nil, fmt.Errorf("could not build prototype: %w", err)
 	}
@@ -155,6 +200,7 @@ func (ep *entityPrototypes) buildPrototype(id string, blocks []*EntityBlock) (*e
 		loweredEntity.fields,
 		nil,
 	)
+	e.Id = id
 
 	for _, c := range loweredEntity.components {
 		e.Add(c)
@@ -175,6 +221,7 @@ func (ep *entityPrototypes) buildPrototype(id string, blocks []*EntityBlock) (*e
 				eventful.AddRule(command, r)
 			}
 		}
+		actions.NumberSchedules(id, eventful.Rules)
 	}
 
 	for _, block := range blocks {
@@ -191,13 +238,13 @@ func (ep *entityPrototypes) buildPrototype(id string, blocks []*EntityBlock) (*e
 				// populate pending children map
 				componentType, err := entities.ParseComponentType(block.Component.Name)
 				if err != nil {
-					return nil, fmt.Errorf("could not build prototype '%s': %w", id, err)
+					return nil, located(block.Component.Pos, fmt.Errorf("could not build prototype '%s': %w", id, err))
 				}
 
 				// get list of strings from expression
 				childrenStrings, err := immediateEvalExpressionAs(f.Value, models.KindStringList)
 				if err != nil {
-					return nil, fmt.Errorf("could not get children list for prototype '%s': %w", id, err)
+					return nil, located(f.Pos, fmt.Errorf("could not get children list for prototype '%s': %w", id, err))
 				}
 
 				ep.childrenPlan[id][componentType] =
@@ -210,13 +257,16 @@ func (ep *entityPrototypes) buildPrototype(id string, blocks []*EntityBlock) (*e
 }
 
 // recursively expand traits in entities
-func (ep *entityPrototypes) lowerEntity(id string, blocks []*EntityBlock) (*LoweredEntity, error) {
+func (ep *entityPrototypes) lowerEntity(id string, pos lexer.Position, blocks []*EntityBlock) (*LoweredEntity, error) {
 	if _, ok := ep.visiting[id]; ok {
-		return nil, fmt.Errorf("cycle detected at %q", id)
+		return nil, located(pos, fmt.Errorf("cycle detected at %q", id))
 	}
 	ep.visiting[id] = struct{}{}
 	defer func() { delete(ep.visiting, id) }()
 
+	// errors are collected so every bad block is reported, not just the first
+	var errs ErrorList
+
 	var name string
 	var description string
 	var aliases []string
@@ -231,7 +281,8 @@ func (ep *entityPrototypes) lowerEntity(id string, blocks []*EntityBlock) (*Lowe
 			// process reaction
 			rules, err := block.Reaction.Build()
 			if err != nil {
-				return nil, err
+				errs.Add(block.Reaction.Pos, fmt.Errorf("could not process reaction in '%s': %w", id, err))
+				continue
 			}
 			// rules at the entity level come first
 			for _, command := range block.Reaction.Commands {
@@ -241,21 +292,28 @@ func (ep *entityPrototypes) lowerEntity(id string, blocks []*EntityBlock) (*Lowe
 			// process component into prototype without children
 			comp, err := block.Component.Build()
 			if err != nil {
-				return nil, fmt.Errorf("could not process component %s: %w", block.Component.Name, err)
+				errs.Add(block.Component.Pos, fmt.Errorf("could not process component %s: %w", block.Component.Name, err))
+				continue
 			}
 			components = append(components, comp)
 		} else if block.Trait != nil {
-			// TODO this dereferences a nil pointer if the trait doesn't exist
-			loweredTrait, err := ep.lowerEntity(block.Trait.Name, ep.traitsById[block.Trait.Name].Blocks)
+			trait, ok := ep.traitsById[block.Trait.Name]
+			if !ok {
+				errs.Add(block.Trait.Pos, fmt.Errorf("%w '%s'", ErrUnknownTrait, block.Trait.Name))
+				continue
+			}
+			loweredTrait, err := ep.lowerEntity(block.Trait.Name, trait.Pos, trait.Blocks)
 			if err != nil {
-				return nil, fmt.Errorf("could not process trait '%s': %w", block.Trait.Name, err)
+				errs.Add(block.Trait.Pos, fmt.Errorf("could not process trait '%s': %w", block.Trait.Name, err))
+				continue
 			}
 
 			// first write over fields that were passed into trait
 			for _, f := range block.Trait.Fields {
 				value, err := immediateEvalExpression(f.Value)
 				if err != nil {
-					return nil, fmt.Errorf("could not get process trait '%s' field '%s': %w", block.Trait.Name, f.Key, err)
+					errs.Add(f.Pos, fmt.Errorf("could not get process trait '%s' field '%s': %w", block.Trait.Name, f.Key, err))
+					continue
 				}
 
 				// only include fields passed into trait that aren't already defined
@@ -282,49 +340,61 @@ func (ep *entityPrototypes) lowerEntity(id string, blocks []*EntityBlock) (*Lowe
 			f := block.Field
 			value, err := immediateEvalExpression(block.Field.Value)
 			if err != nil {
-				return nil, fmt.Errorf("could not get process field '%s' for entity '%s': %w", block.Field.Key, id, err)
+				errs.Add(f.Pos, fmt.Errorf("could not get process field '%s' for entity '%s': %w", block.Field.Key, id, err))
+				continue
 			}
 
 			switch f.Key {
 			case "name":
 				if value.K != models.KindString {
-					return nil, fmt.Errorf("name must be a string")
+					errs.Add(f.Pos, fmt.Errorf("name must be a string"))
+					continue
 				}
 				name = value.S
 			case "description":
 				if value.K != models.KindString {
-					return nil, fmt.Errorf("description must be a string")
+					errs.Add(f.Pos, fmt.Errorf("description must be a string"))
+					continue
 				}
 				description = value.S
 			case "aliases":
 				if value.K != models.KindStringList {
-					return nil, fmt.Errorf("aliases must be a string list")
+					errs.Add(f.Pos, fmt.Errorf("aliases must be a string list"))
+					continue
 				}
 				aliases = value.SL
 			case "tags":
 				if value.K != models.KindStringList {
-					return nil, fmt.Errorf("tags must be a string list")
+					errs.Add(f.Pos, fmt.Errorf("tags must be a string list"))
+					continue
 				}
 				tags = value.SL
 			default:
 				fields[f.Key] = value
 			}
 		} else {
-			return nil, fmt.Errorf("could not expand empty entity block")
+			errs.Add(block.Pos, fmt.Errorf("could not expand empty entity block"))
 		}
 	}
 
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+
 	// only do verification if at a top level entity
 	if len(ep.visiting) == 1 {
 		// verify name, description, and aliases are set. Empty tags is ok
 		if name == "" {
-			return nil, fmt.Errorf("entity '%s' has no name", id)
+			errs.Add(pos, fmt.Errorf("entity '%s' has no name", id))
 		}
 		if description == "" {
-			return nil, fmt.Errorf("entity '%s' has no description", id)
+			errs.Add(pos, fmt.Errorf("entity '%s' has no description", id))
 		}
 		if len(aliases) == 0 {
-			return nil, fmt.Errorf("entity '%s' has no aliases", id)
+			errs.Add(pos, fmt.Errorf("entity '%s' has no aliases", id))
+		}
+		if err := errs.Err(); err != nil {
+			return nil, err
 		}
 	}
 
@@ -340,23 +410,24 @@ func (ep *entityPrototypes) lowerEntity(id string, blocks []*EntityBlock) (*Lowe
 }
 
 // loop through prototypes and instantiate them into a map of entities by name
-func (ep *entityPrototypes) instantiatePrototypes() (map[string]*entities.Entity, error) {
+func (ep *entityPrototypes) instantiatePrototypes(errs *ErrorList) map[string]*entities.Entity {
 	out := make(map[string]*entities.Entity, len(ep.prototypesById))
-	for name := range ep.prototypesById {
+	for name, be := range ep.prototypesById {
 		entity, err := ep.instantiate(name, nil)
 		if err != nil {
-			return nil, fmt.Errorf("could not instantiate '%s': %w", name, err)
+			errs.Add(be.def.Pos, fmt.Errorf("could not instantiate '%s': %w", name, err))
+			continue
 		}
 		out[name] = entity
 	}
-	return out, nil
+	return out
 }
 
 // recursively instantiate a named prototype and wire up children for all child-holding components.
 func (ep *entityPrototypes) instantiate(id string, parent entities.ComponentWithChildren) (*entities.Entity, error) {
 	be, ok := ep.prototypesById[id]
 	if !ok {
-		return nil, fmt.Errorf("unknown prototype %q", id)
+		return nil, fmt.Errorf("%w %q", ErrUnknownPrototype, id)
 	}
 	if _, ok := ep.visiting[id]; ok {
 		return nil, fmt.Errorf("cycle detected at %q", id)
@@ -373,7 +444,8 @@ func (ep *entityPrototypes) instantiate(id string, parent entities.ComponentWith
 			for _, childName := range slot {
 				childInst, err := ep.instantiate(childName, rm)
 				if err != nil {
-					return nil, err
+					// unknown children are reported where they're listed
+					return nil, located(be.def.Pos, err)
 				}
 				rm.AddChild(childInst)
 			}
@@ -386,7 +458,8 @@ func (ep *entityPrototypes) instantiate(id string, parent entities.ComponentWith
 			for _, childName := range slot {
 				childInst, err := ep.instantiate(childName, inventory)
 				if err != nil {
-					return nil, err
+					// unknown children are reported where they're listed
+					return nil, located(be.def.Pos, err)
 				}
 				inventory.AddChild(childInst)
 			}
@@ -399,7 +472,8 @@ func (ep *entityPrototypes) instantiate(id string, parent entities.ComponentWith
 			for _, childName := range slot {
 				childInst, err := ep.instantiate(childName, container)
 				if err != nil {
-					return nil, err
+					// unknown children are reported where they're listed
+					return nil, located(be.def.Pos, err)
 				}
 				container.AddChild(childInst)
 			}
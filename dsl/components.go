@@ -2,15 +2,32 @@ package dsl
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"example.com/mud/models"
+	"example.com/mud/parser/commands"
 	"example.com/mud/world/entities"
 	"example.com/mud/world/entities/components"
+	"github.com/alecthomas/participle/v2/lexer"
 )
 
 type ComponentDef struct {
+	Pos lexer.Position
+
 	Name   string      `parser:"@Ident"`
-	Fields []*FieldDef `parser:"'{' { @@ } '}'"`
+	Exits  []*ExitDef  `parser:"'{' { 'exit' @@"`
+	Fields []*FieldDef `parser:"| @@ } '}'"`
+}
+
+// ExitDef is an exit of a room with properties of its own, such as a door.
+type ExitDef struct {
+	Pos lexer.Position
+
+	Direction string      `parser:"@String"`
+	Room      string      `parser:"'to' @String"`
+	When      *WhenBlock  `parser:"[ '{' { 'when' @@"`
+	Fields    []*FieldDef `parser:"| @@ } '}' ]"`
 }
 
 type componentBuilder func(def *ComponentDef) (entities.Component, error)
@@ -25,9 +42,15 @@ func init() {
 	registerComponentBuilder("Room", buildRoom)
 	registerComponentBuilder("Inventory", buildInventory)
 	registerComponentBuilder("Container", buildContainer)
+	registerComponentBuilder("Gmcp", buildGmcp)
+	registerComponentBuilder("Behavior", buildBehavior)
 }
 
 func (def *ComponentDef) Build() (entities.Component, error) {
+	if len(def.Exits) > 0 && def.Name != "Room" {
+		return nil, fmt.Errorf("%s: only rooms have exits", strings.ToLower(def.Name))
+	}
+
 	if b, ok := componentBuilders[def.Name]; ok {
 		return b(def)
 	}
@@ -39,22 +62,23 @@ func buildRoom(def *ComponentDef) (entities.Component, error) {
 	rm.GetChildren().SetPrefix("In the room")
 
 	for _, f := range def.Fields {
-		// exits are the only place where maps are supported for now
-		// TODO support maps elsewhere
-		if f.Key == "exits" {
-			m := f.Value.AsMap()
-			if m == nil {
-				m = map[string]string{}
-			}
-			rm.Exits = m
-			continue
-		}
-
 		value, err := immediateEvalExpression(f.Value)
 		if err != nil {
 			return nil, fmt.Errorf("could not get value '%s' for Room: %w", f.Key, err)
 		}
 		switch f.Key {
+		case "exits":
+			if value.K != models.KindMap {
+				return nil, fmt.Errorf("room: exits must be a map")
+			}
+
+			for direction, roomId := range value.M {
+				if roomId.K != models.KindString {
+					return nil, fmt.Errorf("room: exit '%s' must lead to a room id", direction)
+				}
+				direction = commands.NormalizeExitName(direction)
+				rm.Exits[direction] = components.NewExit(direction, roomId.S)
+			}
 		case "prefix":
 			if value.K != models.KindString {
 				return nil, fmt.Errorf("room: prefix must be string")
@@ -75,15 +99,120 @@ func buildRoom(def *ComponentDef) (entities.Component, error) {
 			}
 
 			rm.MapColor = value.S
+		case "zone":
+			if value.K != models.KindString {
+				return nil, fmt.Errorf("room: zone must be string")
+			}
+			rm.Zone = value.S
+		case "x", "y":
+			if value.K != models.KindInt {
+				return nil, fmt.Errorf("room: %s must be an int", f.Key)
+			}
+
+			if f.Key == "x" {
+				rm.X = value.I
+			} else {
+				rm.Y = value.I
+			}
+			rm.Placed = true
+		case "landmark":
+			if value.K != models.KindBool {
+				return nil, fmt.Errorf("room: landmark must be a boolean")
+			}
+			rm.Landmark = value.B
+		case "links":
+			if value.K != models.KindString {
+				return nil, fmt.Errorf("room: links must be string")
+			}
+
+			switch value.S {
+			case components.LinksCheck, components.LinksAuto, components.LinksNone:
+				rm.Links = value.S
+			default:
+				return nil, fmt.Errorf("room: links must be %q, %q or %q", components.LinksCheck, components.LinksAuto, components.LinksNone)
+			}
 		case "children":
 			continue
 		default:
 			return nil, fmt.Errorf("room: unknown field %s", f.Key)
 		}
 	}
+	for _, exitDef := range def.Exits {
+		if _, ok := rm.Exits[commands.NormalizeExitName(exitDef.Direction)]; ok {
+			return nil, fmt.Errorf("room: exit '%s' defined more than once", exitDef.Direction)
+		}
+
+		exit, err := exitDef.Build()
+		if err != nil {
+			return nil, fmt.Errorf("room: %w", err)
+		}
+		rm.Exits[exit.Direction] = exit
+	}
+
 	return rm, nil
 }
 
+func (def *ExitDef) Build() (*components.Exit, error) {
+	exit := components.NewExit(commands.NormalizeExitName(def.Direction), def.Room)
+
+	when, err := def.When.Build()
+	if err != nil {
+		return nil, fmt.Errorf("exit '%s': %w", def.Direction, err)
+	}
+	exit.When = when
+
+	for _, f := range def.Fields {
+		value, err := immediateEvalExpression(f.Value)
+		if err != nil {
+			return nil, fmt.Errorf("could not get value '%s' for exit '%s': %w", f.Key, def.Direction, err)
+		}
+
+		switch f.Key {
+		case "door", "closed", "locked", "oneWay", "hidden":
+			if value.K != models.KindBool {
+				return nil, fmt.Errorf("exit '%s': %s must be a boolean", def.Direction, f.Key)
+			}
+		case "key", "fail":
+			if value.K != models.KindString {
+				return nil, fmt.Errorf("exit '%s': %s must be string", def.Direction, f.Key)
+			}
+		case "aliases":
+			if value.K != models.KindStringList {
+				return nil, fmt.Errorf("exit '%s': aliases must be a string list", def.Direction)
+			}
+		default:
+			return nil, fmt.Errorf("exit '%s': unknown field %s", def.Direction, f.Key)
+		}
+
+		switch f.Key {
+		case "door":
+			exit.Door = value.B
+		case "closed":
+			exit.Closed = value.B
+		case "locked":
+			exit.Locked = value.B
+		case "oneWay":
+			exit.OneWay = value.B
+		case "hidden":
+			exit.Hidden = value.B
+		case "key":
+			exit.KeyId = value.S
+		case "fail":
+			exit.Fail = value.S
+		case "aliases":
+			for _, alias := range value.SL {
+				exit.Aliases = append(exit.Aliases, commands.NormalizeExitName(alias))
+			}
+		}
+	}
+
+	// only doors close, and only closed doors are locked
+	exit.Door = exit.Door || exit.Closed || exit.Locked || exit.KeyId != ""
+	exit.Closed = exit.Closed || exit.Locked
+
+	return exit, nil
+}
+
 func buildInventory(def *ComponentDef) (entities.Component, error) {
 	inventory := components.NewInventory()
 	for _, f := range def.Fields {
@@ -139,3 +268,55 @@ func buildContainer(def *ComponentDef) (entities.Component, error) {
 	}
 	return container, nil
 }
+
+func buildGmcp(def *ComponentDef) (entities.Component, error) {
+	gmcp := components.NewGmcp()
+	for _, f := range def.Fields {
+		value, err := immediateEvalExpression(f.Value)
+		if err != nil {
+			return nil, fmt.Errorf("could not get value '%s' for Gmcp: %w", f.Key, err)
+		}
+
+		switch f.Key {
+		case "package":
+			if value.K != models.KindString {
+				return nil, fmt.Errorf("gmcp: package must be string")
+			}
+			gmcp.Package = value.S
+		case "fields":
+			if value.K != models.KindStringList {
+				return nil, fmt.Errorf("gmcp: fields must be a string list")
+			}
+			gmcp.Fields = value.SL
+		default:
+			return nil, fmt.Errorf("gmcp: unknown field %s", f.Key)
+		}
+	}
+	return gmcp, nil
+}
+
+func buildBehavior(def *ComponentDef) (entities.Component, error) {
+	behavior := components.NewBehavior()
+	for _, f := range def.Fields {
+		value, err := immediateEvalExpression(f.Value)
+		if err != nil {
+			return nil, fmt.Errorf("could not get value '%s' for Behavior: %w", f.Key, err)
+		}
+
+		switch f.Key {
+		case "tick":
+			if value.K != models.KindInt || value.I < 0 {
+				return nil, fmt.Errorf("behavior: tick must be a number of seconds")
+			}
+			behavior.Tick = time.Duration(value.I) * time.Second
+		case "wander":
+			if value.K != models.KindInt || value.I < 0 || value.I > 100 {
+				return nil, fmt.Errorf("behavior: wander must be a percent chance, from 0 to 100")
+			}
+			behavior.Wander = value.I
+		default:
+			return nil, fmt.Errorf("behavior: unknown field %s", f.Key)
+		}
+	}
+	return behavior, nil
+}
@@ -25,6 +25,7 @@ type CondAtom struct {
 	Paren      *ConditionDef             `parser:"  '(' @@ ')'"`
 	Not        *NotCondition             `parser:"| @@"`
 	Expr       *ExprCondition            `parser:"| @@"`
+	Each       *EachCondition            `parser:"| @@"`
 	HasTag     *HasTagCondition          `parser:"| @@"`
 	IsPresent  *IsPresentCondition       `parser:"| @@"`
 	RolesEqual *EventRolesEqualCondition `parser:"| @@"`
@@ -40,6 +41,15 @@ type ExprCondition struct {
 	Expr *Expression `parser:"'expr' '{' @@ '}'"`
 }
 
+// EachCondition goes through a list or the keys of a map, e.g.
+// any key in keys(source.stats) { expr { source.stats[key] >= 3 } }
+type EachCondition struct {
+	Quantifier string          `parser:"@( 'any' | 'all' )"`
+	Name       string          `parser:"@Ident 'in'"`
+	Collection *Expression     `parser:"@@"`
+	Conds      []*ConditionDef `parser:"'{' { @@ } '}'"`
+}
+
 type HasTagCondition struct {
 	Target string `parser:"@Ident"`
 	Tag    string `parser:"'has' 'tag' @String"`
@@ -100,6 +110,8 @@ func (def *CondAtom) Build() (entities.Condition, error) {
 		return def.Not.Build()
 	case def.Expr != nil:
 		return def.Expr.Build()
+	case def.Each != nil:
+		return def.Each.Build()
 	case def.HasTag != nil:
 		return def.HasTag.Build()
 	case def.IsPresent != nil:
@@ -131,6 +143,32 @@ func (def *ExprCondition) Build() (entities.Condition, error) {
 	return &conditions.ExpressionTrue{Expression: expression}, nil
 }
 
+func (def *EachCondition) Build() (entities.Condition, error) {
+	if _, err := entities.ParseEventRole(def.Name); err == nil {
+		return nil, fmt.Errorf("'%s' is an event role, it can't name items", def.Name)
+	}
+
+	collection, err := def.Collection.Build()
+	if err != nil {
+		return nil, fmt.Errorf("%s %s in: %w", def.Quantifier, def.Name, err)
+	}
+
+	conds := make([]entities.Condition, len(def.Conds))
+	for i, cDef := range def.Conds {
+		conds[i], err = cDef.Build()
+		if err != nil {
+			return nil, fmt.Errorf("%s %s in: %w", def.Quantifier, def.Name, err)
+		}
+	}
+
+	return &conditions.Each{
+		Name:       def.Name,
+		Collection: collection,
+		All:        def.Quantifier == "all",
+		Conds:      conds,
+	}, nil
+}
+
 func (def *HasTagCondition) Build() (entities.Condition, error) {
 	eventRole, err := entities.ParseEventRole(def.Target)
 	if err != nil {
@@ -0,0 +1,69 @@
+package dsl
+
+import (
+	"fmt"
+	"strings"
+
+	"example.com/mud/models"
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+// DirectionDef declares a direction players can walk, beyond the compass
+// points, up and down, in and out that come built in.
+type DirectionDef struct {
+	Pos lexer.Position
+
+	Name   string      `parser:"@(Ident | String)"`
+	Fields []*FieldDef `parser:"'{' { @@ } '}'"`
+}
+
+func (def *DirectionDef) Build() (*models.CommandDefinition, error) {
+	cmd := &models.CommandDefinition{
+		Name:      strings.ToLower(def.Name),
+		Aliases:   []string{},
+		Direction: &models.Direction{},
+	}
+
+	for _, f := range def.Fields {
+		value, err := immediateEvalExpression(f.Value)
+		if err != nil {
+			return nil, fmt.Errorf("could not get value '%s' for direction: %w", f.Key, err)
+		}
+
+		switch f.Key {
+		case "aliases":
+			if value.K != models.KindStringList {
+				return nil, fmt.Errorf("direction: aliases must be a string list")
+			}
+			cmd.Aliases = append(cmd.Aliases, value.SL...)
+		case "opposite":
+			if value.K != models.KindString {
+				return nil, fmt.Errorf("direction: opposite must be string")
+			}
+			cmd.Direction.Opposite = value.S
+		case "x", "y":
+			if value.K != models.KindInt {
+				return nil, fmt.Errorf("direction: %s must be an int", f.Key)
+			}
+			if value.I < -1 || value.I > 1 {
+				return nil, fmt.Errorf("direction: %s must be -1, 0 or 1", f.Key)
+			}
+
+			if f.Key == "x" {
+				cmd.Direction.X = value.I
+			} else {
+				cmd.Direction.Y = value.I
+			}
+			cmd.Direction.Mapped = true
+		default:
+			return nil, fmt.Errorf("direction: unknown field %s", f.Key)
+		}
+	}
+
+	// a step of nothing can't be drawn
+	if cmd.Direction.X == 0 && cmd.Direction.Y == 0 {
+		cmd.Direction.Mapped = false
+	}
+
+	return cmd, nil
+}
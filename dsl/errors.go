@@ -0,0 +1,113 @@
+package dsl
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	participle "github.com/alecthomas/participle/v2"
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+var (
+	ErrUnknownPrototype = errors.New("unknown prototype")
+	ErrUnknownTrait     = errors.New("unknown trait")
+)
+
+// Error is a problem in a definition file, at the position it was found.
+type Error struct {
+	Pos lexer.Position
+	Err error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %v", e.Pos, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// ErrorList holds every error found while loading definitions, so builders
+// can fix a whole batch of mistakes at once.
+type ErrorList []*Error
+
+func (l ErrorList) Error() string {
+	if len(l) == 1 {
+		return l[0].Error()
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d errors:", len(l))
+	for _, e := range l {
+		b.WriteString("\n  ")
+		b.WriteString(e.Error())
+	}
+	return b.String()
+}
+
+// Err returns the list as an error sorted by position, or nil if it is empty.
+func (l ErrorList) Err() error {
+	if len(l) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(l, func(i, j int) bool {
+		a, b := l[i].Pos, l[j].Pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return l
+}
+
+// Add records err at pos. Errors that already carry a position keep it, since
+// it is closer to the mistake, and lists are flattened.
+func (l *ErrorList) Add(pos lexer.Position, err error) {
+	var list ErrorList
+	var located *Error
+
+	switch {
+	case err == nil:
+		return
+	case errors.As(err, &list):
+		for _, e := range list {
+			l.add(e)
+		}
+	case errors.As(err, &located):
+		l.add(located)
+	default:
+		l.add(&Error{Pos: pos, Err: err})
+	}
+}
+
+func (l *ErrorList) add(e *Error) {
+	// traits are lowered once per entity using them, only report them once
+	for _, existing := range *l {
+		if existing.Error() == e.Error() {
+			return
+		}
+	}
+	*l = append(*l, e)
+}
+
+// located attaches pos to err, unless err already has a position.
+func located(pos lexer.Position, err error) error {
+	var errs ErrorList
+	errs.Add(pos, err)
+	return errs.Err()
+}
+
+// parseError turns a participle error into one carrying its position.
+func parseError(err error) error {
+	var perr participle.Error
+	if errors.As(err, &perr) {
+		return &Error{Pos: perr.Position(), Err: errors.New(perr.Message())}
+	}
+	return err
+}
@@ -11,11 +11,7 @@ import (
 // Expressions are adapted from a Participle example (https://github.com/alecthomas/participle/blob/master/_examples/expr2/main.go)
 
 type Expression struct {
-	Equality *Equality `parser:"  @@"`
-
-	// pairs are only used for room exits and are not ever passed to the evaluation
-	// TODO support maps as a value in the Orbis Definition language
-	Pairs []KV `parser:"| '{' @@ { ',' @@ } '}'"`
+	Equality *Equality `parser:"@@"`
 }
 
 type Equality struct {
@@ -24,10 +20,12 @@ type Equality struct {
 	Next       *Equality   `parser:"  @@ )*"`
 }
 
+// "in" is also how scheduled actions start, so a comparison is never
+// followed by time units, see ScheduleOnceAction
 type Comparison struct {
 	Addition *Addition   `parser:"@@"`
-	Op       string      `parser:"( @( '>' | '>=' | '<' | '<=' )"`
-	Next     *Comparison `parser:"  @@ )*"`
+	Op       string      `parser:"( @( '>' | '>=' | '<' | '<=' | 'in' )"`
+	Next     *Comparison `parser:"  @@ (?! 'second' | 'seconds' | 'minute' | 'minutes' ) )*"`
 }
 
 type Addition struct {
@@ -43,19 +41,22 @@ type Multiplication struct {
 }
 
 type Unary struct {
-	Op      string   `parser:"  ( @( '!' | '-' | '$d' )"`
-	Unary   *Unary   `parser:"   @@ )"`
-	Primary *Primary `parser:"| @@"`
+	Op      string        `parser:"  ( @( '!' | '-' | '$d' )"`
+	Unary   *Unary        `parser:"   @@ )"`
+	Primary *Primary      `parser:"| @@"`
+	Index   []*Expression `parser:"  { '[' @@ ']' }"`
 }
 
 type Primary struct {
 	Number        *int        `parser:"  @Int"`
 	String        *string     `parser:"| @String"`
 	Bool          *string     `parser:"| @( 'true' | 'false' )"`
+	Call          *Call       `parser:"| @@"`
 	Field         *Field      `parser:"| @@"`
 	SubExpression *Expression `parser:"| '(' @@ ')' "`
 	Nil           bool        `parser:"| @'nil'"`
 	List          *List       `parser:"| @@"`
+	Map           *Map        `parser:"| @@"`
 }
 
 type List struct {
@@ -64,25 +65,27 @@ type List struct {
 	Bools   []string `parser:"| '[' ( 'true' | 'false' ) { ',' ( 'true' | 'false' ) } ']'"`
 }
 
+// Map is a map literal, e.g. { "bread": 2, "cake": 5 }.
+type Map struct {
+	Entries []*MapEntry `parser:"'{' ( @@ { ',' @@ } ','? )? '}'"`
+}
+
+type MapEntry struct {
+	Key   string      `parser:"@String"`
+	Value *Expression `parser:"':' @@"`
+}
+
+// Call is a call to a built-in function, e.g. keys(source.stats).
+type Call struct {
+	Name string        `parser:"@Ident '('"`
+	Args []*Expression `parser:"( @@ { ',' @@ } )? ')'"`
+}
+
 type Field struct {
 	Role string `parser:"@Ident"`
 	Name string `parser:"( '.' @Ident )?"`
 }
 
-// In the transition away from the old literals to expressions
-// maps are no longer supported generally. This is to keep maps
-// working for room exits.
-func (e *Expression) AsMap() map[string]string {
-	if len(e.Pairs) == 0 {
-		return nil
-	}
-	m := make(map[string]string, len(e.Pairs))
-	for _, kv := range e.Pairs {
-		m[kv.Key] = kv.Value
-	}
-	return m
-}
-
 func (e *Expression) Build() (expressions.Expression, error) {
 	return e.Equality.Build()
 }
@@ -208,7 +211,19 @@ func (u *Unary) Build() (expressions.Expression, error) {
 
 		return foldConst(&expressions.ExpressionUnary{Op: op, Sub: sub}), nil
 	}
-	return u.Primary.Build()
+
+	expr, err := u.Primary.Build()
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range u.Index {
+		i, err := index.Build()
+		if err != nil {
+			return nil, err
+		}
+		expr = foldConst(&expressions.ExpressionIndex{Sub: expr, Index: i})
+	}
+	return expr, nil
 }
 
 func (p *Primary) Build() (expressions.Expression, error) {
@@ -227,6 +242,10 @@ func (p *Primary) Build() (expressions.Expression, error) {
 		return &expressions.ExpressionConst{V: models.VNil()}, nil
 	case p.Field != nil:
 		eventRole, err := entities.ParseEventRole(p.Field.Role)
+		if err != nil && p.Field.Name == "" {
+			// a name bound by any or all, checked once it is evaluated
+			return &expressions.ExpressionVariable{Name: p.Field.Role}, nil
+		}
 		if err != nil {
 			return nil, fmt.Errorf("could not build has tag condition: %w", err)
 		}
@@ -238,6 +257,34 @@ func (p *Primary) Build() (expressions.Expression, error) {
 		}, nil
 	case p.SubExpression != nil:
 		return p.SubExpression.Build()
+	case p.Call != nil:
+		if !expressions.IsFunction(p.Call.Name) {
+			return nil, fmt.Errorf("unknown function '%s'", p.Call.Name)
+		}
+
+		args := make([]expressions.Expression, len(p.Call.Args))
+		for i, arg := range p.Call.Args {
+			built, err := arg.Build()
+			if err != nil {
+				return nil, err
+			}
+			args[i] = built
+		}
+		return &expressions.ExpressionCall{Name: p.Call.Name, Args: args}, nil
+	case p.Map != nil:
+		entries := make(map[string]expressions.Expression, len(p.Map.Entries))
+		for _, entry := range p.Map.Entries {
+			if _, ok := entries[entry.Key]; ok {
+				return nil, fmt.Errorf("map key '%s' given more than once", entry.Key)
+			}
+
+			built, err := entry.Value.Build()
+			if err != nil {
+				return nil, err
+			}
+			entries[entry.Key] = built
+		}
+		return foldConst(&expressions.ExpressionMap{Entries: entries}), nil
 	case p.List != nil:
 		// Numbers
 		if len(p.List.Numbers) > 0 {
@@ -298,6 +345,8 @@ func mapCmpOp(tok string, l, r expressions.Expression) (expressions.Expression,
 		return &expressions.ExpressionBinary{Op: expressions.OpLt, Left: l, Right: r}, nil
 	case "<=":
 		return &expressions.ExpressionBinary{Op: expressions.OpLe, Left: l, Right: r}, nil
+	case "in":
+		return &expressions.ExpressionBinary{Op: expressions.OpIn, Left: l, Right: r}, nil
 	}
 	return nil, fmt.Errorf("bad comparison op %q", tok)
 }
@@ -346,6 +395,31 @@ func foldConst(n expressions.Expression) expressions.Expression {
 		}
 		t.Sub = k
 		return t
+	case *expressions.ExpressionMap:
+		// maps of constants are constant themselves
+		constant := true
+		for k, entry := range t.Entries {
+			t.Entries[k] = foldConst(entry)
+			if _, ok := t.Entries[k].(*expressions.ExpressionConst); !ok {
+				constant = false
+			}
+		}
+		if constant {
+			if v, err := t.Eval(nil); err == nil {
+				return &expressions.ExpressionConst{V: v}
+			}
+		}
+		return t
+	case *expressions.ExpressionIndex:
+		t.Sub, t.Index = foldConst(t.Sub), foldConst(t.Index)
+		if _, ok := t.Sub.(*expressions.ExpressionConst); ok {
+			if _, ok := t.Index.(*expressions.ExpressionConst); ok {
+				if v, err := t.Eval(nil); err == nil {
+					return &expressions.ExpressionConst{V: v}
+				}
+			}
+		}
+		return t
 	case *expressions.ExpressionBinary:
 		l := foldConst(t.Left)
 		r := foldConst(t.Right)
@@ -0,0 +1,38 @@
+// dsl/expressions_test.go
+package dsl_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"example.com/mud/world/worldtest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpressions_MapValues(t *testing.T) {
+	dir := filepath.Join("testdata", "map_values")
+
+	w, err := worldtest.FromDirectory(dir, "Market")
+	require.NoError(t, err)
+	worldtest.RunTranscript(t, w, filepath.Join(dir, "map_values.txt"))
+
+	// only the baker in the world has pie, not the definition it came from
+	baker, ok := w.Find("Market", "baker")
+	require.True(t, ok)
+	prototype, ok := w.Entity("Baker")
+	require.True(t, ok)
+	require.Len(t, baker.GetField("prices").M, 3)
+	require.Len(t, prototype.GetField("prices").M, 2)
+}
+
+func TestExpressions_ListValues(t *testing.T) {
+	dir := filepath.Join("testdata", "list_values")
+
+	w, err := worldtest.FromDirectory(dir, "Gatehouse")
+	require.NoError(t, err)
+	worldtest.RunTranscript(t, w, filepath.Join(dir, "list_values.txt"))
+
+	player, ok := w.Find("Gatehouse", "alice")
+	require.True(t, ok)
+	require.Equal(t, []string{"brass"}, player.GetField("keys").SL)
+}
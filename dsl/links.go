@@ -0,0 +1,75 @@
+package dsl
+
+import (
+	"sort"
+
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/components"
+)
+
+// linkExits adds the exit leading back for every exit of a room linking
+// automatically, where the room it leads to has nothing that way already.
+func linkExits(entitiesById map[string]*entities.Entity, opposites map[string]string) {
+	ids := make([]string, 0, len(entitiesById))
+	for id := range entitiesById {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		rm, ok := entities.GetComponent[*components.Room](entitiesById[id])
+		if !ok || rm.Links != components.LinksAuto {
+			continue
+		}
+
+		for _, exit := range sortedExits(rm) {
+			back, ok := reverseExitOf(exit, entitiesById, opposites)
+			if !ok {
+				continue
+			}
+
+			target := entitiesById[exit.RoomId]
+			targetRoom, _ := entities.GetComponent[*components.Room](target)
+			if _, exists := targetRoom.Exits[back]; !exists {
+				targetRoom.Exits[back] = exit.Reverse(back, id)
+			}
+		}
+	}
+}
+
+// reverseExitOf returns the direction leading back through exit, if there
+// should be one: the exit goes both ways, in a direction with an opposite,
+// to a room that isn't twisty.
+func reverseExitOf(exit *components.Exit, entitiesById map[string]*entities.Entity, opposites map[string]string) (string, bool) {
+	if exit.OneWay {
+		return "", false
+	}
+
+	back, ok := opposites[exit.Direction]
+	if !ok {
+		return "", false
+	}
+
+	target, ok := entitiesById[exit.RoomId]
+	if !ok {
+		return "", false
+	}
+	targetRoom, ok := entities.GetComponent[*components.Room](target)
+	if !ok || targetRoom.Links == components.LinksNone {
+		return "", false
+	}
+
+	return back, true
+}
+
+// sortedExits lists every exit of rm, hidden ones too, by direction.
+func sortedExits(rm *components.Room) []*components.Exit {
+	exits := make([]*components.Exit, 0, len(rm.Exits))
+	for _, exit := range rm.Exits {
+		exits = append(exits, exit)
+	}
+	sort.Slice(exits, func(i, j int) bool {
+		return exits[i].Direction < exits[j].Direction
+	})
+	return exits
+}
@@ -0,0 +1,15 @@
+// dsl/links_test.go
+package dsl_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"example.com/mud/world/worldtest"
+)
+
+// commands are registered globally, so these tests don't run in parallel
+
+func TestLinkExits(t *testing.T) {
+	worldtest.RunTranscripts(t, filepath.Join("testdata", "links"), "Hall")
+}
@@ -10,20 +10,61 @@ import (
 	"example.com/mud/models"
 	"example.com/mud/world/entities"
 	participle "github.com/alecthomas/participle/v2"
+	"github.com/alecthomas/participle/v2/lexer"
 )
 
 func LoadEntitiesFromDirectory(directoryName string) (map[string]*entities.Entity, []*models.CommandDefinition, error) {
+	ast, err := parseDirectory(directoryName, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entities, commands, err := Compile(ast)
+	return entities, commands, err
+}
+
+// LoadEntitiesFromString compiles definitions held in memory, with filename
+// used in error positions.
+func LoadEntitiesFromString(filename string, source string) (map[string]*entities.Entity, []*models.CommandDefinition, error) {
+	parser, err := buildParser()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ast, err := parser.ParseString(filename, source)
+	if err != nil {
+		return nil, nil, located(lexer.Position{Filename: filename}, parseError(err))
+	}
+
+	entities, commands, err := Compile(ast)
+	return entities, commands, err
+}
+
+func buildParser() (*participle.Parser[DSL], error) {
 	parser, err := participle.Build[DSL](
 		participle.Lexer(DslLexer),
 		participle.Elide("Whitespace", "Comment"),
 		participle.Unquote("String"),
+		participle.UseLookahead(participle.MaxLookahead),
 	)
 	if err != nil {
-		return nil, nil, fmt.Errorf("parser build failed %w", err)
+		return nil, fmt.Errorf("parser build failed %w", err)
+	}
+	return parser, nil
+}
+
+// parse every .mud file under directoryName into one syntax tree
+func parseDirectory(directoryName string, announce bool) (*DSL, error) {
+	parser, err := buildParser()
+	if err != nil {
+		return nil, err
 	}
 
 	var ast = &DSL{}
 
+	// keep parsing after a bad file, so every syntax error is reported at once
+	var errs ErrorList
+
 	err = filepath.WalkDir(directoryName, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return fmt.Errorf("something went wrong: %v", err)
@@ -33,25 +74,30 @@ func LoadEntitiesFromDirectory(directoryName string) (map[string]*entities.Entit
 			return nil
 		}
 
-		fmt.Println("Parsing DSL file:", path)
+		if announce {
+			fmt.Println("Parsing DSL file:", path)
+		}
 
 		data, err := os.ReadFile(path)
 		if err != nil {
 			return fmt.Errorf("failed to read %s: %v", path, err)
 		}
 
-		fileSyntaxTree, err := parser.ParseString("", string(data))
+		fileSyntaxTree, err := parser.ParseString(path, string(data))
 		if err != nil {
-			return fmt.Errorf("failed to parse %s: %v", path, err)
+			errs.Add(lexer.Position{Filename: path}, parseError(err))
+			return nil
 		}
 
 		ast.Declarations = append(ast.Declarations, fileSyntaxTree.Declarations...)
 		return nil
 	})
 	if err != nil {
-		return nil, nil, fmt.Errorf("error walking DSL directory: %w", err)
+		return nil, fmt.Errorf("error walking DSL directory: %w", err)
+	}
+	if err := errs.Err(); err != nil {
+		return nil, err
 	}
 
-	entities, commands, err := Compile(ast)
-	return entities, commands, err
+	return ast, nil
 }
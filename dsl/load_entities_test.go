@@ -0,0 +1,123 @@
+// dsl/load_entities_test.go
+package dsl
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadEntitiesFromDirectory_Errors(t *testing.T) {
+	t.Parallel()
+
+	type tc struct {
+		name  string
+		files map[string]string
+		want  []string
+	}
+
+	cases := []tc{
+		{
+			name: "syntax errors in every file are reported",
+			files: map[string]string{
+				"a.mud": "entity Lamp {\n  name is \"Lamp\"\n  description is\n}\n",
+				"b.mud": "entity Rug {\n  name is \"Rug\" ]\n}\n",
+			},
+			want: []string{"a.mud:4:1", "b.mud:2:17"},
+		},
+		{
+			name: "compile errors are reported together",
+			files: map[string]string{
+				"rooms.mud": "entity Hall {\n" +
+					"  name is 5\n" +
+					"  description is \"A hall.\"\n" +
+					"  aliases is [\"hall\"]\n" +
+					"  component Room {\n" +
+					"    children is [\"Ghost\"]\n" +
+					"  }\n" +
+					"}\n" +
+					"\n" +
+					"entity Hall {\n" +
+					"  name is \"Hall\"\n" +
+					"}\n" +
+					"\n" +
+					"entity Rug {\n" +
+					"  name is \"Rug\"\n" +
+					"}\n",
+			},
+			want: []string{
+				"rooms.mud:2:3: name must be a string",
+				"rooms.mud:10:8: duplicate entity Hall, first defined at",
+				"rooms.mud:14:8: entity 'Rug' has no description",
+				"rooms.mud:14:8: entity 'Rug' has no aliases",
+			},
+		},
+		{
+			name: "unknown children are reported at the parent",
+			files: map[string]string{
+				"rooms.mud": "entity Hall {\n" +
+					"  name is \"Hall\"\n" +
+					"  description is \"A hall.\"\n" +
+					"  aliases is [\"hall\"]\n" +
+					"  component Room {\n" +
+					"    children is [\"Ghost\"]\n" +
+					"  }\n" +
+					"}\n",
+			},
+			want: []string{`rooms.mud:1:8: unknown prototype "Ghost"`},
+		},
+		{
+			name: "bad exits are reported",
+			files: map[string]string{
+				"rooms.mud": "entity Hall {\n" +
+					"  name is \"Hall\"\n" +
+					"  description is \"A hall.\"\n" +
+					"  aliases is [\"hall\"]\n" +
+					"  component Room {\n" +
+					"    exit \"north\" to \"Hall\" {\n" +
+					"      creaky is true\n" +
+					"    }\n" +
+					"  }\n" +
+					"}\n" +
+					"\n" +
+					"entity Box {\n" +
+					"  name is \"Box\"\n" +
+					"  description is \"A box.\"\n" +
+					"  aliases is [\"box\"]\n" +
+					"  component Container {\n" +
+					"    exit \"in\" to \"Hall\"\n" +
+					"  }\n" +
+					"}\n",
+			},
+			want: []string{
+				"rooms.mud:5:13: could not process component Room: room: exit 'north': unknown field creaky",
+				"rooms.mud:16:13: could not process component Container: container: only rooms have exits",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			for name, content := range c.files {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+			}
+
+			_, _, err := LoadEntitiesFromDirectory(dir)
+			require.Error(t, err)
+
+			var list ErrorList
+			require.True(t, errors.As(err, &list))
+			require.Len(t, list, len(c.want))
+			for _, want := range c.want {
+				require.Contains(t, err.Error(), filepath.Join(dir, want))
+			}
+		})
+	}
+}
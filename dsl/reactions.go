@@ -4,9 +4,12 @@ import (
 	"fmt"
 
 	"example.com/mud/world/entities"
+	"github.com/alecthomas/participle/v2/lexer"
 )
 
 type ReactionDef struct {
+	Pos lexer.Position
+
 	Commands []string   `parser:"@Ident { ',' @Ident }"`
 	Rules    []*RuleDef `parser:"'{' { @@ } '}'"`
 }
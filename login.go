@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"example.com/mud/accounts"
+)
+
+const (
+	minPasswordLength = 4
+	maxLoginAttempts  = 3
+)
+
+var errLoginFailed = errors.New("login failed")
+
+// login asks for the password of an existing account, or walks the player
+// through choosing one for a new account.
+func login(c client, store *accounts.Store, name string) (*accounts.Account, error) {
+	account, err := store.Load(name)
+	if errors.Is(err, accounts.ErrNotFound) {
+		return createAccount(c, store, name)
+	} else if err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; attempt < maxLoginAttempts; attempt++ {
+		password, err := readPassword(c, "Password: ")
+		if err != nil {
+			return nil, err
+		}
+
+		if accounts.CheckPassword(account.PasswordHash, password) {
+			return account, nil
+		}
+
+		c.WriteLine("That is not the password I remember.")
+	}
+
+	c.WriteLine("Begone, impostor!")
+	return nil, errLoginFailed
+}
+
+func createAccount(c client, store *accounts.Store, name string) (*accounts.Account, error) {
+	c.WriteLine(fmt.Sprintf("I don't know anyone named %s. Welcome, stranger!", name))
+
+	for attempt := 0; attempt < maxLoginAttempts; attempt++ {
+		password, err := readPassword(c, "Choose a password: ")
+		if err != nil {
+			return nil, err
+		}
+
+		if len(password) < minPasswordLength {
+			c.WriteLine(fmt.Sprintf("Your password must be at least %d characters long.", minPasswordLength))
+			continue
+		}
+
+		confirm, err := readPassword(c, "Repeat your password: ")
+		if err != nil {
+			return nil, err
+		}
+
+		if password != confirm {
+			c.WriteLine("Those passwords don't match.")
+			continue
+		}
+
+		return store.Create(name, password)
+	}
+
+	c.WriteLine("Come back when you've settled on a password.")
+	return nil, errLoginFailed
+}
+
+// readPassword prompts with echo turned off, so the password never shows on screen.
+func readPassword(c client, prompt string) (string, error) {
+	if err := c.SetEcho(false); err != nil {
+		return "", err
+	}
+	if err := c.Prompt(prompt); err != nil {
+		return "", err
+	}
+
+	line, err := c.ReadLine()
+
+	if echoErr := c.SetEcho(true); echoErr != nil && err == nil {
+		err = echoErr
+	}
+	// the newline typed by the player was not echoed either
+	c.WriteLine("")
+
+	return strings.TrimSpace(line), err
+}
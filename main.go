@@ -1,189 +1,59 @@
 package main
 
 import (
-	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"log"
 	"net"
-	"strconv"
-	"strings"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
+	"example.com/mud/accounts"
 	"example.com/mud/config"
 	"example.com/mud/dsl"
 	"example.com/mud/parser/commands"
 	"example.com/mud/world"
-	"example.com/mud/world/entities"
-	"example.com/mud/world/player"
 )
 
-func handleConnection(conn net.Conn, gameWorld *world.World, cfg *config.Config) {
-	defer conn.Close()
-	reader := bufio.NewReader(conn)
-	var name string
+const dataDirectory = "data/"
 
-	for {
-
-		if _, err := fmt.Fprint(conn, "What is your name, weary adventurer? "); err != nil {
-			return
-		}
-
-		name, _ = reader.ReadString('\n')
-		name = strings.TrimSpace(name)
-
-		vdn := player.NameValidation(name)
-		if vdn != "" {
-			fmt.Fprint(conn, vdn)
-			continue
-		}
-		break
-	}
-
-	inbox := make(chan string, 64)
-	player, err := gameWorld.AddPlayer(name, inbox)
-	if err != nil {
-		err := fmt.Errorf("error adding player: %w", err)
-
-		fmt.Println(err.Error())
-		fmt.Fprintln(conn, err.Error())
-		return
-	}
-
-	message, err := player.OpeningMessage()
-	if err != nil {
-		err := fmt.Errorf("error printing opening message: %w", err)
-
-		fmt.Println(err.Error())
-		fmt.Fprintln(conn, err.Error())
-
-		return
-	} else {
-		fmt.Fprintln(conn, message)
-	}
-
-	// start consuming incoming messages
-	go handleConnectionIncoming(conn, inbox)
-
-	// notify when outgoing messages end
-	done := make(chan struct{})
-	go func() {
-		handleConnectionOutgoing(conn, gameWorld, player, cfg)
-		close(done)
-	}()
+func saveSnapshotEvery(ctx context.Context, gameWorld *world.World, path string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
 
-	// Wait until the outgoing loop ends
-	<-done
-}
-
-func handleConnectionIncoming(conn net.Conn, inbox chan string) {
-	go func() {
-		for msg := range inbox {
-			// Use CRLF for telnet clients
-			fmt.Fprint(conn, msg+"\r\n")
-		}
-	}()
-}
-
-func handleConnectionOutgoing(conn net.Conn, gameWorld *world.World, player *player.Player, cfg *config.Config) {
-	scanner := bufio.NewScanner(conn)
 	for {
-		if !scanner.Scan() {
-			break
-		}
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" {
-			continue
-		}
-		if strings.ToLower(line) == "quit" {
-			break
-		}
-
-		// check if player has pending multi-part messages
-		if p := player.Pending; p != nil {
-			n, nerr := strconv.Atoi(line)
-			if nerr == nil {
-				slot := p.Ambiguity.Slots[p.StepIndex]
-				if n >= 1 && n <= len(slot.Matches) {
-					p.Selected[slot.Role] = n - 1
-					p.StepIndex++
-					if p.StepIndex < len(p.Ambiguity.Slots) {
-						// continue prompting current slot
-						promptCurrentSlot(conn, p)
-					} else {
-						chosen := make(map[string]*entities.Entity, len(p.Selected))
-						for _, s := range p.Ambiguity.Slots {
-							idx := p.Selected[s.Role]
-							chosen[s.Role] = s.Matches[idx].Entity
-						}
-						out, execErr := p.Ambiguity.Execute(chosen)
-						player.Pending = nil
-						if execErr != nil {
-							fmt.Fprintln(conn, execErr.Error())
-						} else if out != "" {
-							fmt.Fprintln(conn, out)
-						}
-					}
-				}
-				continue
-			}
-			// non-number input falls through and removes pending action
-			player.Pending = nil
-		}
-
-		if coolDownTime := player.CooldownRemaining(); coolDownTime > 0 {
-			fmt.Fprintf(conn, "You need to catch your breath. Try again in %.1fs\r\n", coolDownTime.Seconds())
-			continue
-		}
-
-		message, err := gameWorld.Parse(player, line)
-		if err != nil {
-			var amb *entities.AmbiguityError
-			if errors.As(err, &amb) {
-				player.Pending = &entities.PendingAction{
-					Ambiguity: amb,
-					StepIndex: 0,
-					Selected:  map[string]int{},
-				}
-				promptCurrentSlot(conn, player.Pending)
-				continue
+		select {
+		case <-ticker.C:
+			if err := gameWorld.SaveSnapshot(path); err != nil {
+				fmt.Println("Error saving world:", err)
 			}
-
-			err := fmt.Errorf("error received: %w", err)
-
-			fmt.Println(err.Error())
-			fmt.Fprintln(conn, err.Error())
-		} else if message != "" {
-			fmt.Fprintln(conn, message)
+		case <-ctx.Done():
+			return
 		}
-
-		player.StartCooldown(time.Duration(cfg.PlayerRateLimit) * time.Millisecond)
 	}
-
-	if err := scanner.Err(); err != nil {
-		fmt.Println("Connection error:", err)
-	}
-
-	gameWorld.DisconnectPlayer(player)
-	fmt.Printf("Connection closed\n")
 }
 
-func promptCurrentSlot(conn net.Conn, p *entities.PendingAction) {
-	slot := p.Ambiguity.Slots[p.StepIndex]
-	fmt.Fprintln(conn, slot.Prompt)
-	for i, opt := range slot.Matches {
-		fmt.Fprintf(conn, "  %d) %s\r\n", i+1, opt.Text)
+func main() {
+	// `mud check` only lints the definitions
+	if len(os.Args) > 1 && os.Args[1] == "check" {
+		os.Exit(runCheck(os.Args[2:], os.Stdout))
 	}
-}
 
-func main() {
+	// the first interrupt shuts down gracefully, a second one kills the server
+	ctx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+
 	// load configuration file
 	cfg, err := config.Load("config.yaml")
 	if err != nil {
 		log.Fatalf("failed to load config: %v", err)
 	}
 
-	entityMap, cmds, err := dsl.LoadEntitiesFromDirectory("data/")
+	entityMap, cmds, err := dsl.LoadEntitiesFromDirectory(dataDirectory)
 	if err != nil {
 		log.Fatalf("failed to load DSL entities: %v", err)
 	}
@@ -202,21 +72,90 @@ func main() {
 	}
 
 	gameWorld := world.NewWorld(entityMap, cfg.StartingRoom)
+	gameWorld.Admins = cfg.Admins
+	gameWorld.SnapshotPath = cfg.SnapshotFile
+	gameWorld.DataDirectory = dataDirectory
+
+	gameWorld.RevealMap = cfg.RevealMap
+	gameWorld.TravelStep = time.Duration(cfg.PlayerRateLimit) * time.Millisecond
+	gameWorld.MissedJobs, err = world.ParseMissedJobs(cfg.MissedJobs)
+	if err != nil {
+		log.Fatalf("failed to load config: %v", err)
+	}
+
+	if cfg.WatchData {
+		go watchDataDirectory(ctx, gameWorld, dataDirectory, dataWatchInterval)
+	}
+
+	if cfg.SnapshotFile != "" {
+		snapshot, err := world.LoadSnapshot(cfg.SnapshotFile)
+		if err == nil {
+			if err := gameWorld.Restore(snapshot); err != nil {
+				log.Fatalf("failed to restore world: %v", err)
+			}
+			fmt.Printf("Restored world saved at %s\n", snapshot.SavedAt.Format(time.RFC1123))
+		} else if !errors.Is(err, os.ErrNotExist) {
+			log.Fatalf("failed to restore world: %v", err)
+		}
+
+		if cfg.SnapshotInterval > 0 {
+			go saveSnapshotEvery(ctx, gameWorld, cfg.SnapshotFile, time.Duration(cfg.SnapshotInterval)*time.Second)
+		}
+	}
+
+	var store *accounts.Store
+	if cfg.AccountsDirectory != "" {
+		store, err = accounts.NewStore(cfg.AccountsDirectory)
+		if err != nil {
+			log.Fatalf("failed to open account store: %v", err)
+		}
+	}
+
+	sessions := newSessionTracker()
 
 	listener, err := net.Listen("tcp", ":4000")
 	if err != nil {
 		panic(err)
 	}
-	defer listener.Close()
 
 	fmt.Println("MUD server listening on port 4000...")
 
-	for {
-		conn, err := listener.Accept()
-		if err != nil {
-			fmt.Println("Error accepting connection:", err)
-			continue
+	var webServer *http.Server
+	if cfg.WebAddress != "" {
+		webServer = &http.Server{
+			Addr:    cfg.WebAddress,
+			Handler: websocketHandler(gameWorld, store, sessions, cfg),
+		}
+
+		go func() {
+			fmt.Printf("WebSocket gateway listening on %s...\n", cfg.WebAddress)
+			if err := webServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Fatalf("websocket gateway: %v", err)
+			}
+		}()
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if errors.Is(err, net.ErrClosed) {
+				return
+			} else if err != nil {
+				fmt.Println("Error accepting connection:", err)
+				continue
+			}
+			go handleTelnetConnection(conn, gameWorld, store, sessions, cfg)
 		}
-		go handleConnection(conn, gameWorld, cfg)
+	}()
+
+	<-ctx.Done()
+	stopSignals()
+
+	// stop accepting connections, hijacked websockets are unaffected by Close
+	listener.Close()
+	if webServer != nil {
+		webServer.Close()
 	}
+
+	shutdown(gameWorld, sessions, cfg)
 }
@@ -5,6 +5,8 @@
 package mocks
 
 import (
+	"time"
+
 	"example.com/mud/world/scheduler"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -75,3 +77,104 @@ func (_c *MockScheduler_Add_Call) RunAndReturn(run func(job *scheduler.Job)) *Mo
 	_c.Run(run)
 	return _c
 }
+
+// Now provides a mock function for the type MockScheduler
+func (_mock *MockScheduler) Now() time.Time {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Now")
+	}
+
+	var r0 time.Time
+	if returnFunc, ok := ret.Get(0).(func() time.Time); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+	return r0
+}
+
+// MockScheduler_Now_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Now'
+type MockScheduler_Now_Call struct {
+	*mock.Call
+}
+
+// Now is a helper method to define mock.On call
+func (_e *MockScheduler_Expecter) Now() *MockScheduler_Now_Call {
+	return &MockScheduler_Now_Call{Call: _e.mock.On("Now")}
+}
+
+func (_c *MockScheduler_Now_Call) Run(run func()) *MockScheduler_Now_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockScheduler_Now_Call) Return(time1 time.Time) *MockScheduler_Now_Call {
+	_c.Call.Return(time1)
+	return _c
+}
+
+func (_c *MockScheduler_Now_Call) RunAndReturn(run func() time.Time) *MockScheduler_Now_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CancelOwnedBy provides a mock function for the type MockScheduler
+func (_mock *MockScheduler) CancelOwnedBy(owner any, label string) int {
+	ret := _mock.Called(owner, label)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelOwnedBy")
+	}
+
+	var r0 int
+	if returnFunc, ok := ret.Get(0).(func(any, string) int); ok {
+		r0 = returnFunc(owner, label)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	return r0
+}
+
+// MockScheduler_CancelOwnedBy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CancelOwnedBy'
+type MockScheduler_CancelOwnedBy_Call struct {
+	*mock.Call
+}
+
+// CancelOwnedBy is a helper method to define mock.On call
+//   - owner any
+//   - label string
+func (_e *MockScheduler_Expecter) CancelOwnedBy(owner interface{}, label interface{}) *MockScheduler_CancelOwnedBy_Call {
+	return &MockScheduler_CancelOwnedBy_Call{Call: _e.mock.On("CancelOwnedBy", owner, label)}
+}
+
+func (_c *MockScheduler_CancelOwnedBy_Call) Run(run func(owner any, label string)) *MockScheduler_CancelOwnedBy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 any
+		if args[0] != nil {
+			arg0 = args[0].(any)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockScheduler_CancelOwnedBy_Call) Return(n int) *MockScheduler_CancelOwnedBy_Call {
+	_c.Call.Return(n)
+	return _c
+}
+
+func (_c *MockScheduler_CancelOwnedBy_Call) RunAndReturn(run func(owner any, label string) int) *MockScheduler_CancelOwnedBy_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -4,6 +4,10 @@ type CommandDefinition struct {
 	Name     string
 	Aliases  []string
 	Patterns []CommandPattern
+
+	// set for directions, which are walked by typing their name or an alias
+	// rather than matching patterns
+	Direction *Direction
 }
 
 type CommandPattern struct {
@@ -1,10 +1,27 @@
 package models
 
 const (
-	DirectionNorth = "north"
-	DirectionEast  = "east"
-	DirectionSouth = "south"
-	DirectionWest  = "west"
-	DirectionUp    = "up"
-	DirectionDown  = "down"
+	DirectionNorth     = "north"
+	DirectionNortheast = "northeast"
+	DirectionEast      = "east"
+	DirectionSoutheast = "southeast"
+	DirectionSouth     = "south"
+	DirectionSouthwest = "southwest"
+	DirectionWest      = "west"
+	DirectionNorthwest = "northwest"
+	DirectionUp        = "up"
+	DirectionDown      = "down"
+	DirectionIn        = "in"
+	DirectionOut       = "out"
 )
+
+// Direction describes a way out of rooms players can walk by name, such as
+// north.
+type Direction struct {
+	// the direction leading back, e.g. south for north
+	Opposite string
+
+	// the step it takes across the map, if it is drawn on it
+	X, Y   int
+	Mapped bool
+}
@@ -18,3 +18,8 @@ var SGR = map[string]string{
 }
 
 const Tab = "  "
+
+// Preformatted starts text laid out in columns, such as maps, which clients
+// mustn't wrap. It's an empty SGR reset, so it shows as nothing wherever it
+// ends up.
+const Preformatted = "\x1b[m"
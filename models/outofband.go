@@ -0,0 +1,10 @@
+package models
+
+import "encoding/json"
+
+// OutOfBand is structured data sent to clients alongside prose, such as GMCP
+// packages like "Room.Info" or "Char.Vitals".
+type OutOfBand struct {
+	Package string
+	Data    json.RawMessage
+}
@@ -19,13 +19,16 @@ const (
 )
 
 type Value struct {
-	K  Kind
-	I  int
-	IL []int
-	S  string
-	SL []string
-	B  bool
-	BL []bool
+	K  Kind     `json:"k"`
+	I  int      `json:"i,omitempty"`
+	IL []int    `json:"il,omitempty"`
+	S  string   `json:"s,omitempty"`
+	SL []string `json:"sl,omitempty"`
+	B  bool     `json:"b,omitempty"`
+	BL []bool   `json:"bl,omitempty"`
+	// maps are never changed in place, as entities copied from the same
+	// prototype share them
+	M map[string]Value `json:"m,omitempty"`
 }
 
 func VInt(i int) Value    { return Value{K: KindInt, I: i} }
@@ -33,6 +36,19 @@ func VStr(s string) Value { return Value{K: KindString, S: s} }
 func VBool(b bool) Value  { return Value{K: KindBool, B: b} }
 func VNil() Value         { return Value{K: KindNil} }
 
+func VMap(m map[string]Value) Value { return Value{K: KindMap, M: m} }
+
+// WithKey returns a copy of the map v with key set to value, leaving v as it
+// was.
+func (v Value) WithKey(key string, value Value) Value {
+	m := make(map[string]Value, len(v.M)+1)
+	for k, existing := range v.M {
+		m[k] = existing
+	}
+	m[key] = value
+	return VMap(m)
+}
+
 func VList[T any](xs []T) (Value, error) {
 	var zero T
 	et := reflect.TypeOf(zero)
@@ -114,3 +130,29 @@ func FromAny(x any) (Value, error) {
 		return Value{}, fmt.Errorf("unsupported literal type %T", x)
 	}
 }
+
+// Any converts a value into plain go types, e.g. for encoding to JSON.
+func (v Value) Any() any {
+	switch v.K {
+	case KindInt:
+		return v.I
+	case KindIntList:
+		return v.IL
+	case KindString:
+		return v.S
+	case KindStringList:
+		return v.SL
+	case KindBool:
+		return v.B
+	case KindBoolList:
+		return v.BL
+	case KindMap:
+		m := make(map[string]any, len(v.M))
+		for k, value := range v.M {
+			m[k] = value.Any()
+		}
+		return m
+	default:
+		return nil
+	}
+}
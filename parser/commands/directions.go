@@ -0,0 +1,172 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"example.com/mud/models"
+)
+
+// Directions holds every direction by its name, built in or defined.
+var Directions = map[string]*models.Direction{}
+
+// DirectionAliases maps what players may type, e.g. "n", to a direction.
+var DirectionAliases = map[string]string{}
+
+var builtInDirections = []*models.CommandDefinition{
+	direction(models.DirectionNorth, models.DirectionSouth, 0, -1, "n"),
+	direction(models.DirectionNortheast, models.DirectionSouthwest, 1, -1, "ne"),
+	direction(models.DirectionEast, models.DirectionWest, 1, 0, "e"),
+	direction(models.DirectionSoutheast, models.DirectionNorthwest, 1, 1, "se"),
+	direction(models.DirectionSouth, models.DirectionNorth, 0, 1, "s"),
+	direction(models.DirectionSouthwest, models.DirectionNortheast, -1, 1, "sw"),
+	direction(models.DirectionWest, models.DirectionEast, -1, 0, "w"),
+	direction(models.DirectionNorthwest, models.DirectionSoutheast, -1, -1, "nw"),
+
+	// these lead off the map rather than across it
+	{
+		Name:      models.DirectionUp,
+		Aliases:   []string{"u"},
+		Direction: &models.Direction{Opposite: models.DirectionDown},
+	},
+	{
+		Name:      models.DirectionDown,
+		Aliases:   []string{"d"},
+		Direction: &models.Direction{Opposite: models.DirectionUp},
+	},
+	{
+		Name:      models.DirectionIn,
+		Aliases:   []string{"inside"},
+		Direction: &models.Direction{Opposite: models.DirectionOut},
+	},
+	{
+		Name:      models.DirectionOut,
+		Aliases:   []string{"outside"},
+		Direction: &models.Direction{Opposite: models.DirectionIn},
+	},
+}
+
+func direction(name, opposite string, x, y int, aliases ...string) *models.CommandDefinition {
+	return &models.CommandDefinition{
+		Name:    name,
+		Aliases: aliases,
+		Direction: &models.Direction{
+			Opposite: opposite,
+			X:        x,
+			Y:        y,
+			Mapped:   true,
+		},
+	}
+}
+
+// CanonicalDirection returns the direction name typed, if it names or is an
+// alias of one. Otherwise name is returned as given, as it may still be the
+// name of an exit, e.g. "wardrobe".
+func CanonicalDirection(name string) string {
+	name = NormalizeExitName(name)
+	if canonical, ok := DirectionAliases[name]; ok {
+		return canonical
+	}
+	return name
+}
+
+// OppositeDirection returns the direction leading back the way direction
+// came, if there is one.
+func OppositeDirection(direction string) (string, bool) {
+	d, ok := Directions[CanonicalDirection(direction)]
+	if !ok || d.Opposite == "" {
+		return "", false
+	}
+	return d.Opposite, true
+}
+
+// NormalizeExitName lowercases name and squashes its spaces, the way input
+// is tokenized, so exits with long names can be typed.
+func NormalizeExitName(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
+
+// OppositeDirections pairs each built-in direction and each direction among
+// defs with the one leading back, without registering any of them.
+func OppositeDirections(defs []*models.CommandDefinition) (map[string]string, error) {
+	directions := map[string]*models.Direction{}
+	aliases := map[string]string{}
+
+	for _, cd := range append(append([]*models.CommandDefinition{}, builtInDirections...), defs...) {
+		if cd.Direction == nil {
+			continue
+		}
+		if err := registerDirection(directions, aliases, cd); err != nil {
+			return nil, err
+		}
+	}
+	if err := pairDirections(directions); err != nil {
+		return nil, err
+	}
+
+	opposites := make(map[string]string, len(directions))
+	for name, d := range directions {
+		if d.Opposite != "" {
+			opposites[name] = d.Opposite
+		}
+	}
+	return opposites, nil
+}
+
+func registerDirection(directions map[string]*models.Direction, aliases map[string]string, cd *models.CommandDefinition) error {
+	if len(cd.Patterns) > 0 {
+		return fmt.Errorf("direction '%s' can't have patterns", cd.Name)
+	}
+
+	name := NormalizeExitName(cd.Name)
+	if name == "" {
+		return fmt.Errorf("direction has no name")
+	}
+
+	d := *cd.Direction
+	d.Opposite = NormalizeExitName(d.Opposite)
+	directions[name] = &d
+
+	for _, alias := range append([]string{name}, cd.Aliases...) {
+		alias = NormalizeExitName(alias)
+		if existing, ok := aliases[alias]; ok && existing != name {
+			return fmt.Errorf("direction '%s': alias '%s' is already used by '%s'", name, alias, existing)
+		}
+		aliases[alias] = name
+	}
+
+	return nil
+}
+
+// pairDirections makes each direction's opposite lead back to it, so a
+// direction only needs its opposite given once.
+func pairDirections(directions map[string]*models.Direction) error {
+	names := make([]string, 0, len(directions))
+	for name := range directions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		d := directions[name]
+		if d.Opposite == "" {
+			continue
+		}
+
+		opposite, ok := directions[d.Opposite]
+		if !ok {
+			return fmt.Errorf("direction '%s': opposite '%s' is not a direction", name, d.Opposite)
+		}
+
+		switch opposite.Opposite {
+		case "":
+			opposite.Opposite = name
+		case name:
+		default:
+			return fmt.Errorf("direction '%s': opposite '%s' already leads back to '%s'", name, d.Opposite, opposite.Opposite)
+		}
+	}
+
+	return nil
+}
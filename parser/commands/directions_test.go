@@ -0,0 +1,177 @@
+package commands
+
+import (
+	"testing"
+
+	"example.com/mud/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterDirection(t *testing.T) {
+	t.Parallel()
+
+	type tc struct {
+		name        string
+		defs        []*models.CommandDefinition
+		wantAliases map[string]string
+		wantErr     string
+	}
+
+	cases := []tc{
+		{
+			name: "names and aliases are typed as players would",
+			defs: []*models.CommandDefinition{
+				{Name: "Climb  Ivy", Aliases: []string{"IVY"}, Direction: &models.Direction{Opposite: "Down"}},
+			},
+			wantAliases: map[string]string{"climb ivy": "climb ivy", "ivy": "climb ivy"},
+		},
+		{
+			name: "aliases already used by another direction",
+			defs: []*models.CommandDefinition{
+				{Name: "widdershins", Aliases: []string{"w"}, Direction: &models.Direction{}},
+				{Name: "west", Aliases: []string{"w"}, Direction: &models.Direction{}},
+			},
+			wantErr: "direction 'west': alias 'w' is already used by 'widdershins'",
+		},
+		{
+			name: "defined again under the same name",
+			defs: []*models.CommandDefinition{
+				{Name: "sunwise", Aliases: []string{"sun"}, Direction: &models.Direction{}},
+				{Name: "sunwise", Aliases: []string{"sun"}, Direction: &models.Direction{}},
+			},
+			wantAliases: map[string]string{"sunwise": "sunwise", "sun": "sunwise"},
+		},
+		{
+			name: "no name",
+			defs: []*models.CommandDefinition{
+				{Name: "  ", Direction: &models.Direction{}},
+			},
+			wantErr: "direction has no name",
+		},
+		{
+			name: "patterns",
+			defs: []*models.CommandDefinition{
+				{Name: "sunwise", Patterns: []models.CommandPattern{{}}, Direction: &models.Direction{}},
+			},
+			wantErr: "direction 'sunwise' can't have patterns",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			directions := map[string]*models.Direction{}
+			aliases := map[string]string{}
+			var err error
+			for _, cd := range c.defs {
+				if err = registerDirection(directions, aliases, cd); err != nil {
+					break
+				}
+			}
+			if c.wantErr != "" {
+				require.EqualError(t, err, c.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.wantAliases, aliases)
+		})
+	}
+
+	// the definition isn't changed, so it can be registered again on reload
+	cd := &models.CommandDefinition{Name: "sunwise", Direction: &models.Direction{Opposite: "Widdershins"}}
+	directions := map[string]*models.Direction{}
+	require.NoError(t, registerDirection(directions, map[string]string{}, cd))
+	require.Equal(t, "widdershins", directions["sunwise"].Opposite)
+	require.Equal(t, "Widdershins", cd.Direction.Opposite)
+}
+
+func TestPairDirections(t *testing.T) {
+	t.Parallel()
+
+	type tc struct {
+		name       string
+		directions map[string]*models.Direction
+		want       map[string]string
+		wantErr    string
+	}
+
+	cases := []tc{
+		{
+			name: "opposites given one way lead back",
+			directions: map[string]*models.Direction{
+				"widdershins": {Opposite: "sunwise"},
+				"sunwise":     {},
+			},
+			want: map[string]string{"widdershins": "sunwise", "sunwise": "widdershins"},
+		},
+		{
+			name: "opposites given both ways",
+			directions: map[string]*models.Direction{
+				"widdershins": {Opposite: "sunwise"},
+				"sunwise":     {Opposite: "widdershins"},
+			},
+			want: map[string]string{"widdershins": "sunwise", "sunwise": "widdershins"},
+		},
+		{
+			name: "directions without opposites",
+			directions: map[string]*models.Direction{
+				"sideways": {},
+			},
+			want: map[string]string{"sideways": ""},
+		},
+		{
+			name: "opposite isn't a direction",
+			directions: map[string]*models.Direction{
+				"widdershins": {Opposite: "sunwise"},
+			},
+			wantErr: "direction 'widdershins': opposite 'sunwise' is not a direction",
+		},
+		{
+			name: "opposite already leads somewhere else",
+			directions: map[string]*models.Direction{
+				"sunwise":     {Opposite: "anticlock"},
+				"anticlock":   {},
+				"widdershins": {Opposite: "sunwise"},
+			},
+			wantErr: "direction 'widdershins': opposite 'sunwise' already leads back to 'anticlock'",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := pairDirections(c.directions)
+			if c.wantErr != "" {
+				require.EqualError(t, err, c.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			got := map[string]string{}
+			for name, d := range c.directions {
+				got[name] = d.Opposite
+			}
+			require.Equal(t, c.want, got)
+		})
+	}
+}
+
+func TestOppositeDirections(t *testing.T) {
+	t.Parallel()
+
+	opposites, err := OppositeDirections([]*models.CommandDefinition{
+		{Name: "widdershins", Direction: &models.Direction{Opposite: "sunwise"}},
+		{Name: "sunwise", Direction: &models.Direction{}},
+		{Name: "dance"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "widdershins", opposites["sunwise"])
+	require.Equal(t, "south", opposites["north"])
+	require.NotContains(t, opposites, "dance")
+
+	// nothing is registered for players to type
+	_, ok := Directions["sunwise"]
+	require.False(t, ok)
+}
@@ -8,43 +8,35 @@ import (
 
 var Commands = map[string]struct{}{}
 
-var DirectionAliases = map[string]string{
-	"n":     models.DirectionNorth,
-	"north": models.DirectionNorth,
-
-	"s":     models.DirectionSouth,
-	"south": models.DirectionSouth,
-
-	"e":    models.DirectionEast,
-	"east": models.DirectionEast,
-
-	"w":    models.DirectionWest,
-	"west": models.DirectionWest,
-
-	"u":  models.DirectionUp,
-	"up": models.DirectionUp,
-
-	"d":    models.DirectionDown,
-	"down": models.DirectionDown,
-}
-
 var VerbAliases = map[string]string{}
 
 var Patterns = []models.Pattern{}
 
 func RegisterBuiltInCommands() error {
-	return RegisterCommands([]*models.CommandDefinition{
+	defs := append([]*models.CommandDefinition{}, builtInDirections...)
+	return RegisterCommands(append(defs,
 		&helpCommand,
 		&inventoryCommand,
 		&lookCommand,
 		&moveCommand,
 		&mapCommand,
 		&trackCommand,
-	})
+		&travelCommand,
+		&saveCommand,
+		&reloadCommand,
+		&jobsCommand,
+	))
 }
 
 func RegisterCommands(defs []*models.CommandDefinition) error {
 	for _, cd := range defs {
+		if cd.Direction != nil {
+			if err := registerDirection(Directions, DirectionAliases, cd); err != nil {
+				return err
+			}
+			continue
+		}
+
 		if len(cd.Aliases) == 0 {
 			return fmt.Errorf("command '%s' has no aliases", cd.Name)
 		}
@@ -65,5 +57,65 @@ func RegisterCommands(defs []*models.CommandDefinition) error {
 			})
 		}
 	}
+	return pairDirections(Directions)
+}
+
+// CommandSet is every command, pattern and direction registered at once, so
+// a new set can be built before any of it is put in place.
+type CommandSet struct {
+	commands         map[string]struct{}
+	verbAliases      map[string]string
+	patterns         []models.Pattern
+	directions       map[string]*models.Direction
+	directionAliases map[string]string
+}
+
+func registeredCommands() *CommandSet {
+	return &CommandSet{
+		commands:         Commands,
+		verbAliases:      VerbAliases,
+		patterns:         Patterns,
+		directions:       Directions,
+		directionAliases: DirectionAliases,
+	}
+}
+
+// Install makes the set the registered commands.
+func (s *CommandSet) Install() {
+	Commands, VerbAliases, Patterns = s.commands, s.verbAliases, s.patterns
+	Directions, DirectionAliases = s.directions, s.directionAliases
+}
+
+// BuildCommands registers the built-in commands plus defs into a new set,
+// leaving the registered commands as they are.
+func BuildCommands(defs []*models.CommandDefinition) (*CommandSet, error) {
+	old := registeredCommands()
+	defer old.Install()
+
+	Commands = map[string]struct{}{}
+	VerbAliases = map[string]string{}
+	Patterns = []models.Pattern{}
+	Directions = map[string]*models.Direction{}
+	DirectionAliases = map[string]string{}
+
+	if err := RegisterBuiltInCommands(); err != nil {
+		return nil, err
+	}
+	if err := RegisterCommands(defs); err != nil {
+		return nil, err
+	}
+
+	return registeredCommands(), nil
+}
+
+// ReplaceCommands swaps every registered command for the built-in commands
+// plus defs. If any of them fail to register, the old commands are kept.
+func ReplaceCommands(defs []*models.CommandDefinition) error {
+	set, err := BuildCommands(defs)
+	if err != nil {
+		return fmt.Errorf("replace commands: %w", err)
+	}
+
+	set.Install()
 	return nil
 }
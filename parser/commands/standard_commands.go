@@ -104,7 +104,61 @@ var trackCommand = models.CommandDefinition{
 				models.Lit("track"),
 				models.Slot("target"),
 			},
-			HelpMessage: `Track any entities with a given alias, they appear as "!" on your map.`,
+			HelpMessage: `Track any entities with a given alias, they appear as "!" on your map and you're told which way they are.`,
+		},
+	},
+}
+
+var travelCommand = models.CommandDefinition{
+	Name:    "travel",
+	Aliases: []string{"travel"},
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("travel"),
+				models.SlotRest("destination"),
+			},
+			HelpMessage:    "Walk to a room you name, a step at a time.",
+			NoMatchMessage: "Where do you want to travel to?",
+		},
+	},
+}
+
+var saveCommand = models.CommandDefinition{
+	Name:    "save",
+	Aliases: []string{"save"},
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("save"),
+			},
+			HelpMessage: "Save the state of the world (admins only).",
+		},
+	},
+}
+
+var jobsCommand = models.CommandDefinition{
+	Name:    "jobs",
+	Aliases: []string{"jobs"},
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("jobs"),
+			},
+			HelpMessage: "List the jobs waiting to run (admins only).",
+		},
+	},
+}
+
+var reloadCommand = models.CommandDefinition{
+	Name:    "reload",
+	Aliases: []string{"reload"},
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("reload"),
+			},
+			HelpMessage: "Reload the world definitions without restarting (admins only).",
 		},
 	},
 }
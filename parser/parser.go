@@ -96,7 +96,7 @@ func tryMatch(p models.Pattern, tokens []string) (*models.Command, bool) {
 				return nil, false
 			}
 			rest := tokens[ti:]
-			val, ok := validateSlot(pt.SlotName, rest)
+			val, ok := validateSlot(pt.SlotName, rest, true)
 			if !ok {
 				return nil, false
 			}
@@ -109,7 +109,7 @@ func tryMatch(p models.Pattern, tokens []string) (*models.Command, bool) {
 		if ti >= len(tokens) {
 			return nil, false
 		}
-		val, ok := validateSlot(pt.SlotName, []string{tokens[ti]})
+		val, ok := validateSlot(pt.SlotName, []string{tokens[ti]}, false)
 		if !ok {
 			return nil, false
 		}
@@ -129,17 +129,23 @@ func tryMatch(p models.Pattern, tokens []string) (*models.Command, bool) {
 	}, true
 }
 
-func validateSlot(SlotType string, toks []string) (string, bool) {
+func validateSlot(SlotType string, toks []string, rest bool) (string, bool) {
 	switch SlotType {
 	case "direction":
-		if len(toks) != 1 {
+		if len(toks) == 0 {
 			return "", false
 		}
 
-		if canon, ok := commands.DirectionAliases[toks[0]]; ok {
+		if canon, ok := commands.DirectionAliases[strings.Join(toks, " ")]; ok {
 			return canon, true
 		}
 
+		// anything else may be the name of an exit, e.g. "move wardrobe",
+		// but only when it can't be mistaken for another command
+		if rest {
+			return strings.Join(toks, " "), true
+		}
+
 		return "", false
 	default:
 		if len(toks) == 0 {
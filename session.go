@@ -0,0 +1,308 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"example.com/mud/accounts"
+	"example.com/mud/config"
+	"example.com/mud/models"
+	"example.com/mud/world"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/player"
+)
+
+// client is the transport a player is connected through. Everything above it,
+// from the name prompt to disconnecting, is shared by telnet and websocket players.
+type client interface {
+	// ReadLine blocks until the player sends a line of input.
+	ReadLine() (string, error)
+	// Prompt writes text without ending the line.
+	Prompt(text string) error
+	// WriteLine writes text as a complete message.
+	WriteLine(text string) error
+	// WriteData sends structured out-of-band data, if the client accepts it.
+	WriteData(msg models.OutOfBand) error
+	// SetEcho toggles whether what the player types is shown, e.g. for passwords.
+	SetEcho(on bool) error
+	Close() error
+}
+
+func handleSession(c client, gameWorld *world.World, store *accounts.Store, cfg *config.Config) {
+	defer c.Close()
+
+	var name string
+
+	for {
+		if err := c.Prompt("What is your name, weary adventurer? "); err != nil {
+			return
+		}
+
+		line, err := c.ReadLine()
+		if err != nil {
+			return
+		}
+		name = strings.TrimSpace(line)
+
+		vdn := player.NameValidation(name)
+		if vdn != "" {
+			c.Prompt(vdn)
+			continue
+		}
+		break
+	}
+
+	// without a store anyone can play under any name, and nothing is saved
+	var account *accounts.Account
+	var saved *player.State
+	if store != nil {
+		var err error
+		account, err = login(c, store, name)
+		if errors.Is(err, errLoginFailed) {
+			return
+		} else if err != nil {
+			err := fmt.Errorf("error logging in: %w", err)
+
+			fmt.Println(err.Error())
+			c.WriteLine(err.Error())
+			return
+		}
+
+		// the account keeps the capitalization it was created with
+		name = account.Name
+		saved = account.Player
+	}
+
+	inbox := make(chan string, 64)
+	data := make(chan models.OutOfBand, 64)
+	player, err := gameWorld.AddPlayer(name, inbox, data, saved)
+	if errors.Is(err, world.ErrPlayerConnected) {
+		c.WriteLine(fmt.Sprintf("%s is already wandering these lands.", name))
+		return
+	} else if errors.Is(err, world.ErrShuttingDown) {
+		c.WriteLine("The world is ending, come back later.")
+		return
+	} else if err != nil {
+		err := fmt.Errorf("error adding player: %w", err)
+
+		fmt.Println(err.Error())
+		c.WriteLine(err.Error())
+		return
+	}
+
+	message, err := gameWorld.OpeningMessage(player)
+	if err != nil {
+		err := fmt.Errorf("error printing opening message: %w", err)
+
+		fmt.Println(err.Error())
+		c.WriteLine(err.Error())
+
+		gameWorld.DisconnectPlayer(player)
+		return
+	} else {
+		c.WriteLine(message)
+	}
+
+	// start consuming incoming messages until the session ends
+	done := make(chan struct{})
+	defer close(done)
+	go handleSessionIncoming(c, inbox, data, player.FlushOutOfBand, player.Done(), done)
+
+	handleSessionOutgoing(c, gameWorld, player, cfg)
+
+	if account != nil {
+		account.Player = gameWorld.PlayerState(player)
+		if err := store.Save(account); err != nil {
+			fmt.Println("Error saving player:", err)
+		}
+	}
+
+	gameWorld.DisconnectPlayer(player)
+	fmt.Printf("Connection closed\n")
+}
+
+func handleSessionIncoming(c client, inbox chan string, data chan models.OutOfBand, flushData func(), disconnected <-chan struct{}, done chan struct{}) {
+	for {
+		select {
+		case msg := <-inbox:
+			c.WriteLine(msg)
+		case msg := <-data:
+			c.WriteData(msg)
+			// packages that didn't fit in the channel can go now
+			flushData()
+		case <-disconnected:
+			// the world let go of the player, deliver what is left and hang up,
+			// which ends the read loop
+			for {
+				select {
+				case msg := <-inbox:
+					c.WriteLine(msg)
+					continue
+				default:
+				}
+				break
+			}
+			c.Close()
+			return
+		case <-done:
+			return
+		}
+	}
+}
+
+// sessionTracker keeps every open client, so they can be waited on, or
+// closed, when the server shuts down.
+type sessionTracker struct {
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	clients map[client]struct{}
+}
+
+func newSessionTracker() *sessionTracker {
+	return &sessionTracker{
+		clients: make(map[client]struct{}),
+	}
+}
+
+// run handles a session for c, tracking it until the session ends.
+func (t *sessionTracker) run(c client, gameWorld *world.World, store *accounts.Store, cfg *config.Config) {
+	t.mu.Lock()
+	t.clients[c] = struct{}{}
+	t.wg.Add(1)
+	t.mu.Unlock()
+
+	defer func() {
+		t.mu.Lock()
+		delete(t.clients, c)
+		t.mu.Unlock()
+		t.wg.Done()
+	}()
+
+	handleSession(c, gameWorld, store, cfg)
+}
+
+// closeAll hangs up on every client still connected, e.g. players still
+// typing their name.
+func (t *sessionTracker) closeAll() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for c := range t.clients {
+		c.Close()
+	}
+}
+
+// wait blocks until every session has ended, or timeout passes.
+func (t *sessionTracker) wait(timeout time.Duration) bool {
+	finished := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+func handleSessionOutgoing(c client, gameWorld *world.World, player *player.Player, cfg *config.Config) {
+	for {
+		line, err := c.ReadLine()
+		if err != nil {
+			// closed connections are how sessions are ended on shutdown
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				fmt.Println("Connection error:", err)
+			}
+			break
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.ToLower(line) == "quit" {
+			break
+		}
+
+		// check if player has pending multi-part messages
+		if p := player.Pending; p != nil {
+			n, nerr := strconv.Atoi(line)
+			if nerr == nil {
+				slot := p.Ambiguity.Slots[p.StepIndex]
+				if n >= 1 && n <= len(slot.Matches) {
+					p.Selected[slot.Role] = n - 1
+					p.StepIndex++
+					if p.StepIndex < len(p.Ambiguity.Slots) {
+						// continue prompting current slot
+						promptCurrentSlot(c, p)
+					} else {
+						chosen := make(map[string]*entities.Entity, len(p.Selected))
+						for _, s := range p.Ambiguity.Slots {
+							idx := p.Selected[s.Role]
+							chosen[s.Role] = s.Matches[idx].Entity
+						}
+						out, execErr := gameWorld.ResolveAmbiguity(p.Ambiguity, chosen)
+						player.Pending = nil
+						if execErr != nil {
+							c.WriteLine(execErr.Error())
+						} else if out != "" {
+							c.WriteLine(out)
+						}
+					}
+				}
+				continue
+			}
+			// non-number input falls through and removes pending action
+			player.Pending = nil
+		}
+
+		if coolDownTime := player.CooldownRemaining(); coolDownTime > 0 {
+			c.WriteLine(fmt.Sprintf("You need to catch your breath. Try again in %.1fs", coolDownTime.Seconds()))
+			continue
+		}
+
+		message, err := gameWorld.Parse(player, line)
+		if err != nil {
+			var amb *entities.AmbiguityError
+			if errors.As(err, &amb) {
+				player.Pending = &entities.PendingAction{
+					Ambiguity: amb,
+					StepIndex: 0,
+					Selected:  map[string]int{},
+				}
+				promptCurrentSlot(c, player.Pending)
+				continue
+			}
+
+			err := fmt.Errorf("error received: %w", err)
+
+			fmt.Println(err.Error())
+			c.WriteLine(err.Error())
+		} else if message != "" {
+			c.WriteLine(message)
+		}
+
+		player.StartCooldown(time.Duration(cfg.PlayerRateLimit) * time.Millisecond)
+	}
+}
+
+func promptCurrentSlot(c client, p *entities.PendingAction) {
+	slot := p.Ambiguity.Slots[p.StepIndex]
+
+	var b strings.Builder
+	b.WriteString(slot.Prompt)
+	for i, opt := range slot.Matches {
+		fmt.Fprintf(&b, "\n  %d) %s", i+1, opt.Text)
+	}
+
+	c.WriteLine(b.String())
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"example.com/mud/config"
+	"example.com/mud/utils"
+	"example.com/mud/world"
+)
+
+const defaultShutdownMessage = "The world will end in {seconds} seconds!"
+
+// how long players get to be saved and hung up on once the countdown ends
+const sessionShutdownTimeout = 5 * time.Second
+
+// shutdown warns players, lets running commands and jobs finish, then
+// disconnects and saves everyone.
+func shutdown(gameWorld *world.World, sessions *sessionTracker, cfg *config.Config) {
+	fmt.Println("Shutting down...")
+
+	countdown(gameWorld, cfg)
+
+	gameWorld.Broadcast("The world fades to black.")
+	gameWorld.Shutdown()
+	gameWorld.DisconnectAll()
+
+	// players are saved as their sessions end, anyone left is still logging in
+	if !sessions.wait(sessionShutdownTimeout) {
+		sessions.closeAll()
+		sessions.wait(sessionShutdownTimeout)
+	}
+
+	if cfg.SnapshotFile != "" {
+		if err := gameWorld.SaveSnapshot(cfg.SnapshotFile); err != nil {
+			fmt.Println("Error saving world:", err)
+		}
+	}
+
+	fmt.Println("Goodbye.")
+}
+
+// countdown broadcasts the shutdown message when it starts, every ten
+// seconds, and every second for the last five.
+func countdown(gameWorld *world.World, cfg *config.Config) {
+	message := cfg.ShutdownMessage
+	if message == "" {
+		message = defaultShutdownMessage
+	}
+
+	for remaining := cfg.ShutdownCountdown; remaining > 0; remaining-- {
+		if remaining == cfg.ShutdownCountdown || remaining%10 == 0 || remaining <= 5 {
+			text, err := utils.FormatText(message, map[string]string{
+				"seconds": strconv.Itoa(remaining),
+			})
+			if err != nil {
+				fmt.Println("Error formatting shutdown message:", err)
+				text = message
+			}
+
+			gameWorld.Broadcast(text)
+		}
+
+		time.Sleep(time.Second)
+	}
+}
@@ -0,0 +1,393 @@
+package telnet
+
+import (
+	"net"
+	"strings"
+	"sync"
+)
+
+// telnet commands (RFC 854)
+const (
+	SE   byte = 240
+	NOP  byte = 241
+	GA   byte = 249
+	SB   byte = 250
+	WILL byte = 251
+	WONT byte = 252
+	DO   byte = 253
+	DONT byte = 254
+	IAC  byte = 255
+)
+
+// telnet options
+const (
+	OptEcho            byte = 1
+	OptSuppressGoAhead byte = 3
+	OptTerminalType    byte = 24
+	OptNAWS            byte = 31
+	OptGMCP            byte = 201
+)
+
+// terminal type subnegotiation commands (RFC 1091)
+const (
+	ttypeIs   byte = 0
+	ttypeSend byte = 1
+)
+
+type parseState int
+
+const (
+	stateData parseState = iota
+	stateIAC
+	stateOption
+	stateSubOption
+	stateSubData
+	stateSubIAC
+)
+
+// option tracks the negotiated state of a single telnet option on both ends
+// of the connection, along with whether we are waiting on a reply.
+type option struct {
+	local         bool
+	remote        bool
+	pendingLocal  bool
+	pendingRemote bool
+}
+
+// Conn sits between a raw net.Conn and the line readers/writers of a session.
+// Reads have all IAC sequences negotiated and stripped, writes have IAC bytes
+// escaped.
+type Conn struct {
+	net.Conn
+
+	writeMu sync.Mutex
+
+	// read-side parser state, only touched by the reading goroutine
+	state    parseState
+	verb     byte
+	sbOption byte
+	sbData   []byte
+	buf      []byte
+
+	mu           sync.RWMutex
+	options      map[byte]*option
+	width        int
+	height       int
+	terminalType string
+}
+
+// options we are willing to enable on our side, and ask the client to enable on theirs
+var supportedLocal = map[byte]bool{
+	OptEcho:            true,
+	OptSuppressGoAhead: true,
+	OptGMCP:            true,
+}
+
+var supportedRemote = map[byte]bool{
+	OptTerminalType: true,
+	OptNAWS:         true,
+}
+
+func NewConn(conn net.Conn) *Conn {
+	return &Conn{
+		Conn:    conn,
+		options: make(map[byte]*option),
+	}
+}
+
+// Negotiate asks the client for its window size and terminal type. Replies
+// are processed as they arrive during subsequent reads.
+func (c *Conn) Negotiate() error {
+	if err := c.requestRemote(OptNAWS, true); err != nil {
+		return err
+	}
+	if err := c.requestRemote(OptTerminalType, true); err != nil {
+		return err
+	}
+	if err := c.requestLocal(OptSuppressGoAhead, true); err != nil {
+		return err
+	}
+	return c.requestLocal(OptGMCP, true)
+}
+
+// SetEcho toggles whether the client echoes typed input locally. Turning echo
+// off is done by claiming the server will echo, and then not echoing, which
+// is how password prompts are hidden.
+func (c *Conn) SetEcho(on bool) error {
+	return c.requestLocal(OptEcho, !on)
+}
+
+func (c *Conn) Width() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.width
+}
+
+func (c *Conn) Height() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.height
+}
+
+func (c *Conn) TerminalType() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.terminalType
+}
+
+// Color reports whether the client's terminal shows color, going by the
+// terminal type it sent. Clients which never said are assumed to.
+func (c *Conn) Color() bool {
+	return !strings.EqualFold(c.TerminalType(), "dumb")
+}
+
+// GMCPEnabled reports whether the client agreed to receive GMCP messages.
+func (c *Conn) GMCPEnabled() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	o, ok := c.options[OptGMCP]
+	return ok && o.local
+}
+
+// SendGMCP writes a single GMCP message, e.g. package "Room.Info" with a JSON payload.
+func (c *Conn) SendGMCP(pkg string, data []byte) error {
+	msg := []byte{IAC, SB, OptGMCP}
+	for _, b := range append([]byte(pkg+" "), data...) {
+		if b == IAC {
+			msg = append(msg, IAC)
+		}
+		msg = append(msg, b)
+	}
+	msg = append(msg, IAC, SE)
+
+	return c.writeRaw(msg)
+}
+
+func (c *Conn) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if cap(c.buf) < len(p) {
+		c.buf = make([]byte, len(p))
+	}
+
+	for {
+		n, err := c.Conn.Read(c.buf[:len(p)])
+
+		// data is never longer than the raw bytes it came from, so p has room
+		out := p[:0]
+		for _, b := range c.buf[:n] {
+			out = c.consume(b, out)
+		}
+
+		// a read made only of negotiation yields no data, keep reading
+		if len(out) > 0 || err != nil {
+			return len(out), err
+		}
+	}
+}
+
+func (c *Conn) Write(p []byte) (int, error) {
+	escaped := make([]byte, 0, len(p))
+	for _, b := range p {
+		if b == IAC {
+			escaped = append(escaped, IAC)
+		}
+		escaped = append(escaped, b)
+	}
+
+	if err := c.writeRaw(escaped); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (c *Conn) writeRaw(p []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_, err := c.Conn.Write(p)
+	return err
+}
+
+// consume advances the parser by a single byte, appending it to out if it is data.
+func (c *Conn) consume(b byte, out []byte) []byte {
+	switch c.state {
+	case stateData:
+		switch b {
+		case IAC:
+			c.state = stateIAC
+		case 0:
+			// CR NUL is how telnet sends a bare carriage return
+		default:
+			out = append(out, b)
+		}
+	case stateIAC:
+		switch b {
+		case IAC:
+			out = append(out, IAC)
+			c.state = stateData
+		case WILL, WONT, DO, DONT:
+			c.verb = b
+			c.state = stateOption
+		case SB:
+			c.state = stateSubOption
+		default:
+			// NOP, GA, and friends carry no payload
+			c.state = stateData
+		}
+	case stateOption:
+		c.handleOption(c.verb, b)
+		c.state = stateData
+	case stateSubOption:
+		c.sbOption = b
+		c.sbData = c.sbData[:0]
+		c.state = stateSubData
+	case stateSubData:
+		if b == IAC {
+			c.state = stateSubIAC
+		} else {
+			c.sbData = append(c.sbData, b)
+		}
+	case stateSubIAC:
+		switch b {
+		case IAC:
+			c.sbData = append(c.sbData, IAC)
+			c.state = stateSubData
+		case SE:
+			c.handleSubnegotiation(c.sbOption, c.sbData)
+			c.state = stateData
+		default:
+			// malformed subnegotiation, drop it
+			c.state = stateData
+		}
+	}
+
+	return out
+}
+
+func (c *Conn) option(opt byte) *option {
+	o, ok := c.options[opt]
+	if !ok {
+		o = &option{}
+		c.options[opt] = o
+	}
+	return o
+}
+
+// handleOption answers a WILL/WONT/DO/DONT from the client. Replies are only
+// sent when the option's state actually changes, which prevents negotiation loops.
+func (c *Conn) handleOption(verb, opt byte) {
+	c.mu.Lock()
+	o := c.option(opt)
+	var reply []byte
+
+	switch verb {
+	case WILL:
+		wasRemote := o.remote
+		if o.pendingRemote {
+			o.pendingRemote = false
+			o.remote = true
+		} else if !o.remote {
+			if supportedRemote[opt] {
+				o.remote = true
+				reply = []byte{IAC, DO, opt}
+			} else {
+				reply = []byte{IAC, DONT, opt}
+			}
+		}
+		// the terminal type is only sent when asked for
+		if !wasRemote && o.remote && opt == OptTerminalType {
+			reply = append(reply, IAC, SB, OptTerminalType, ttypeSend, IAC, SE)
+		}
+	case WONT:
+		if o.remote && !o.pendingRemote {
+			reply = []byte{IAC, DONT, opt}
+		}
+		o.remote = false
+		o.pendingRemote = false
+	case DO:
+		if o.pendingLocal {
+			o.pendingLocal = false
+			o.local = true
+		} else if !o.local {
+			if supportedLocal[opt] {
+				o.local = true
+				reply = []byte{IAC, WILL, opt}
+			} else {
+				reply = []byte{IAC, WONT, opt}
+			}
+		}
+	case DONT:
+		if o.local && !o.pendingLocal {
+			reply = []byte{IAC, WONT, opt}
+		}
+		o.local = false
+		o.pendingLocal = false
+	}
+	c.mu.Unlock()
+
+	if len(reply) > 0 {
+		c.writeRaw(reply)
+	}
+}
+
+func (c *Conn) handleSubnegotiation(opt byte, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch opt {
+	case OptNAWS:
+		if len(data) != 4 {
+			return
+		}
+		c.width = int(data[0])<<8 | int(data[1])
+		c.height = int(data[2])<<8 | int(data[3])
+	case OptTerminalType:
+		if len(data) < 1 || data[0] != ttypeIs {
+			return
+		}
+		c.terminalType = string(data[1:])
+	}
+}
+
+// requestRemote asks the client to enable or disable an option on its end.
+func (c *Conn) requestRemote(opt byte, enable bool) error {
+	c.mu.Lock()
+	o := c.option(opt)
+	if enable && (o.remote || o.pendingRemote) || !enable && !o.remote && !o.pendingRemote {
+		c.mu.Unlock()
+		return nil
+	}
+	verb := DONT
+	if enable {
+		verb = DO
+		o.pendingRemote = true
+	} else {
+		o.remote = false
+		o.pendingRemote = false
+	}
+	c.mu.Unlock()
+
+	return c.writeRaw([]byte{IAC, verb, opt})
+}
+
+// requestLocal tells the client we will, or won't, perform an option on our end.
+func (c *Conn) requestLocal(opt byte, enable bool) error {
+	c.mu.Lock()
+	o := c.option(opt)
+	if enable && (o.local || o.pendingLocal) || !enable && !o.local && !o.pendingLocal {
+		c.mu.Unlock()
+		return nil
+	}
+	verb := WONT
+	if enable {
+		verb = WILL
+		o.pendingLocal = true
+	} else {
+		o.local = false
+		o.pendingLocal = false
+	}
+	c.mu.Unlock()
+
+	return c.writeRaw([]byte{IAC, verb, opt})
+}
@@ -0,0 +1,213 @@
+package telnet
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeConn feeds canned client bytes to the reader and records what the server writes.
+type fakeConn struct {
+	net.Conn
+	in  *bytes.Reader
+	out bytes.Buffer
+}
+
+func (f *fakeConn) Read(p []byte) (int, error)  { return f.in.Read(p) }
+func (f *fakeConn) Write(p []byte) (int, error) { return f.out.Write(p) }
+
+func newFakeConn(in []byte) *fakeConn {
+	return &fakeConn{in: bytes.NewReader(in)}
+}
+
+func TestConn_Read(t *testing.T) {
+	t.Parallel()
+
+	type tc struct {
+		name         string
+		input        []byte
+		wantData     string
+		wantReply    []byte
+		wantWidth    int
+		wantHeight   int
+		wantTermType string
+	}
+
+	cases := []tc{
+		{
+			name:     "plain data passes through",
+			input:    []byte("look\r\n"),
+			wantData: "look\r\n",
+		},
+		{
+			name:     "escaped IAC is unescaped",
+			input:    []byte{'a', IAC, IAC, 'b'},
+			wantData: "a\xffb",
+		},
+		{
+			name:     "CR NUL is collapsed",
+			input:    []byte{'h', 'i', '\r', 0},
+			wantData: "hi\r",
+		},
+		{
+			name:     "unsupported WILL is refused with DONT",
+			input:    append([]byte{IAC, WILL, 42}, []byte("hi")...),
+			wantData: "hi",
+			wantReply: []byte{
+				IAC, DONT, 42,
+			},
+		},
+		{
+			name:     "unsupported DO is refused with WONT",
+			input:    append([]byte{IAC, DO, 42}, []byte("hi")...),
+			wantData: "hi",
+			wantReply: []byte{
+				IAC, WONT, 42,
+			},
+		},
+		{
+			name: "NAWS subnegotiation sets window size",
+			input: append([]byte{
+				IAC, WILL, OptNAWS,
+				IAC, SB, OptNAWS, 0, 120, 0, 40, IAC, SE,
+			}, []byte("n")...),
+			wantData:   "n",
+			wantReply:  []byte{IAC, DO, OptNAWS},
+			wantWidth:  120,
+			wantHeight: 40,
+		},
+		{
+			name: "NAWS with escaped IAC in the size",
+			input: append([]byte{
+				IAC, SB, OptNAWS, 0, IAC, IAC, 0, 24, IAC, SE,
+			}, []byte("n")...),
+			wantData:   "n",
+			wantWidth:  255,
+			wantHeight: 24,
+		},
+		{
+			name: "terminal type is requested and recorded",
+			input: append(append([]byte{
+				IAC, WILL, OptTerminalType,
+				IAC, SB, OptTerminalType, ttypeIs,
+			}, []byte("MUDLET")...), IAC, SE, 'x'),
+			wantData: "x",
+			wantReply: []byte{
+				IAC, DO, OptTerminalType,
+				IAC, SB, OptTerminalType, ttypeSend, IAC, SE,
+			},
+			wantTermType: "MUDLET",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			fc := newFakeConn(c.input)
+			conn := NewConn(fc)
+
+			data, err := io.ReadAll(conn)
+			require.NoError(t, err)
+
+			require.Equal(t, c.wantData, string(data))
+			require.Equal(t, c.wantReply, nilIfEmpty(fc.out.Bytes()))
+			require.Equal(t, c.wantWidth, conn.Width())
+			require.Equal(t, c.wantHeight, conn.Height())
+			require.Equal(t, c.wantTermType, conn.TerminalType())
+		})
+	}
+}
+
+func TestConn_Negotiation(t *testing.T) {
+	t.Parallel()
+
+	t.Run("replies to our requests are not answered again", func(t *testing.T) {
+		t.Parallel()
+
+		fc := newFakeConn([]byte{IAC, WILL, OptNAWS, IAC, DO, OptSuppressGoAhead, IAC, DO, OptGMCP, 'x'})
+		conn := NewConn(fc)
+
+		require.NoError(t, conn.Negotiate())
+		require.Equal(t, []byte{
+			IAC, DO, OptNAWS,
+			IAC, DO, OptTerminalType,
+			IAC, WILL, OptSuppressGoAhead,
+			IAC, WILL, OptGMCP,
+		}, fc.out.Bytes())
+		fc.out.Reset()
+		require.False(t, conn.GMCPEnabled())
+
+		_, err := io.ReadAll(conn)
+		require.NoError(t, err)
+		require.Empty(t, fc.out.Bytes())
+		require.True(t, conn.GMCPEnabled())
+	})
+
+	t.Run("GMCP messages are framed and escaped", func(t *testing.T) {
+		t.Parallel()
+
+		fc := newFakeConn(nil)
+		conn := NewConn(fc)
+
+		require.NoError(t, conn.SendGMCP("Char.Vitals", []byte{'{', IAC, '}'}))
+		require.Equal(t, append(append([]byte{IAC, SB, OptGMCP}, []byte("Char.Vitals {")...), IAC, IAC, '}', IAC, SE), fc.out.Bytes())
+	})
+
+	t.Run("echo suppression toggles WILL and WONT ECHO", func(t *testing.T) {
+		t.Parallel()
+
+		fc := newFakeConn([]byte{IAC, DO, OptEcho, 'x'})
+		conn := NewConn(fc)
+
+		require.NoError(t, conn.SetEcho(false))
+		require.Equal(t, []byte{IAC, WILL, OptEcho}, fc.out.Bytes())
+
+		// asking twice does not send twice
+		require.NoError(t, conn.SetEcho(false))
+		require.Equal(t, []byte{IAC, WILL, OptEcho}, fc.out.Bytes())
+		fc.out.Reset()
+
+		_, err := io.ReadAll(conn)
+		require.NoError(t, err)
+		require.Empty(t, fc.out.Bytes())
+
+		require.NoError(t, conn.SetEcho(true))
+		require.Equal(t, []byte{IAC, WONT, OptEcho}, fc.out.Bytes())
+	})
+}
+
+func TestConn_Color(t *testing.T) {
+	t.Parallel()
+
+	for termType, want := range map[string]bool{"": true, "MUDLET": true, "DUMB": false, "dumb": false} {
+		input := append(append([]byte{IAC, SB, OptTerminalType, ttypeIs}, []byte(termType)...), IAC, SE, 'x')
+		conn := NewConn(newFakeConn(input))
+
+		_, err := io.ReadAll(conn)
+		require.NoError(t, err)
+		require.Equal(t, want, conn.Color(), termType)
+	}
+}
+
+func TestConn_Write(t *testing.T) {
+	t.Parallel()
+
+	fc := newFakeConn(nil)
+	conn := NewConn(fc)
+
+	n, err := conn.Write([]byte{'a', IAC, 'b'})
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+	require.Equal(t, []byte{'a', IAC, IAC, 'b'}, fc.out.Bytes())
+}
+
+func nilIfEmpty(b []byte) []byte {
+	if len(b) == 0 {
+		return nil
+	}
+	return b
+}
@@ -0,0 +1,52 @@
+package telnet
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"example.com/mud/models"
+	"example.com/mud/utils"
+)
+
+// Wrap breaks the lines of text longer than width at spaces. Color codes take
+// up no room on screen, and words too long for a line are left whole. Text
+// marked models.Preformatted is left as it is.
+func Wrap(text string, width int) string {
+	if width <= 0 || strings.HasPrefix(text, models.Preformatted) {
+		return text
+	}
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = wrapLine(line, width)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func wrapLine(line string, width int) string {
+	if visibleLength(line) <= width {
+		return line
+	}
+
+	var b strings.Builder
+	column := 0
+	for i, word := range strings.Split(line, " ") {
+		length := visibleLength(word)
+		if i > 0 {
+			if column > 0 && column+1+length > width {
+				b.WriteString("\n")
+				column = 0
+			} else {
+				b.WriteString(" ")
+				column++
+			}
+		}
+		b.WriteString(word)
+		column += length
+	}
+	return b.String()
+}
+
+func visibleLength(s string) int {
+	return utf8.RuneCountInString(utils.StripANSI(s))
+}
@@ -0,0 +1,71 @@
+package telnet
+
+import (
+	"testing"
+
+	"example.com/mud/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrap(t *testing.T) {
+	t.Parallel()
+
+	type tc struct {
+		name  string
+		text  string
+		width int
+		want  string
+	}
+
+	cases := []tc{
+		{
+			name:  "short lines are left alone",
+			text:  "A dusty vault.",
+			width: 20,
+			want:  "A dusty vault.",
+		},
+		{
+			name:  "long lines break at spaces",
+			text:  "The quick brown fox jumps over the lazy dog",
+			width: 15,
+			want:  "The quick brown\nfox jumps over\nthe lazy dog",
+		},
+		{
+			name:  "existing line breaks are kept",
+			text:  "A dusty vault.\nExits: north, south",
+			width: 14,
+			want:  "A dusty vault.\nExits: north,\nsouth",
+		},
+		{
+			name:  "color codes take up no room",
+			text:  "\x1b[31mred\x1b[0m \x1b[32mgreen\x1b[0m blue",
+			width: 9,
+			want:  "\x1b[31mred\x1b[0m \x1b[32mgreen\x1b[0m\nblue",
+		},
+		{
+			name:  "words longer than a line are left whole",
+			text:  "a supercalifragilistic word",
+			width: 10,
+			want:  "a\nsupercalifragilistic\nword",
+		},
+		{
+			name:  "preformatted text is left alone",
+			text:  models.Preformatted + "O  O--O\n|  |  |\n@--O  O",
+			width: 4,
+			want:  models.Preformatted + "O  O--O\n|  |  |\n@--O  O",
+		},
+		{
+			name:  "no width leaves text alone",
+			text:  "The quick brown fox jumps over the lazy dog",
+			width: 0,
+			want:  "The quick brown fox jumps over the lazy dog",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, c.want, Wrap(c.text, c.width))
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var sgrRegex = regexp.MustCompile("\x1b\\[([0-9;]*)m")
+
+// class names for SGR parameters, used when rendering for browsers
+var sgrClasses = map[int]string{
+	1:  "ansi-bold",
+	3:  "ansi-italic",
+	4:  "ansi-underline",
+	30: "ansi-black",
+	31: "ansi-red",
+	32: "ansi-green",
+	33: "ansi-yellow",
+	34: "ansi-blue",
+	35: "ansi-magenta",
+	36: "ansi-cyan",
+	37: "ansi-white",
+}
+
+// StripANSI removes all SGR control codes from s.
+func StripANSI(s string) string {
+	return sgrRegex.ReplaceAllString(s, "")
+}
+
+// ANSIToHTML escapes s for HTML and turns SGR control codes into spans with
+// "ansi-*" classes, closing every open span on reset.
+func ANSIToHTML(s string) string {
+	var b strings.Builder
+	open := 0
+
+	last := 0
+	for _, m := range sgrRegex.FindAllStringSubmatchIndex(s, -1) {
+		b.WriteString(html.EscapeString(s[last:m[0]]))
+		last = m[1]
+
+		for _, param := range strings.Split(s[m[2]:m[3]], ";") {
+			n, err := strconv.Atoi(param)
+			if param == "" || err == nil && n == 0 {
+				b.WriteString(strings.Repeat("</span>", open))
+				open = 0
+				continue
+			}
+
+			if class, ok := sgrClasses[n]; ok {
+				b.WriteString(`<span class="` + class + `">`)
+				open++
+			}
+		}
+	}
+	b.WriteString(html.EscapeString(s[last:]))
+	b.WriteString(strings.Repeat("</span>", open))
+
+	return b.String()
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"example.com/mud/world"
+)
+
+const dataWatchInterval = 2 * time.Second
+
+// watchDataDirectory polls the definition files and reloads the world when
+// any of them are added, removed or modified.
+func watchDataDirectory(ctx context.Context, gameWorld *world.World, dir string, interval time.Duration) {
+	last, err := fingerprint(dir)
+	if err != nil {
+		fmt.Println("Error watching data directory:", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		current, err := fingerprint(dir)
+		if err != nil {
+			fmt.Println("Error watching data directory:", err)
+			continue
+		}
+		if current == last {
+			continue
+		}
+		last = current
+
+		fmt.Println("Data directory changed, reloading...")
+		result, err := gameWorld.Reload()
+		if err != nil {
+			fmt.Println("Reload failed, the world is unchanged:", err)
+			continue
+		}
+		fmt.Println("Reloaded world:", result)
+	}
+}
+
+// fingerprint summarizes the name, size and modification time of every .mud file.
+func fingerprint(dir string) (string, error) {
+	var b strings.Builder
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".mud") {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(&b, "%s:%d:%d\n", path, info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("fingerprint %s: %w", dir, err)
+	}
+
+	return b.String(), nil
+}
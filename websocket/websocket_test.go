@@ -0,0 +1,351 @@
+package websocket
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcceptKey(t *testing.T) {
+	t.Parallel()
+
+	// example from RFC 6455 section 1.3
+	require.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", AcceptKey("dGhlIHNhbXBsZSBub25jZQ=="))
+}
+
+func TestUpgrade_Rejected(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := Upgrade(w, r, nil)
+		require.Error(t, err)
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestUpgrade_Origin(t *testing.T) {
+	t.Parallel()
+
+	type tc struct {
+		name    string
+		origin  string
+		allowed []string
+		want    int
+	}
+
+	cases := []tc{
+		{
+			name: "clients without an origin are let in",
+			want: http.StatusSwitchingProtocols,
+		},
+		{
+			name:   "pages from the same host are let in",
+			origin: "http://localhost",
+			want:   http.StatusSwitchingProtocols,
+		},
+		{
+			name:    "allowed origins are let in",
+			origin:  "https://play.example.com",
+			allowed: []string{"https://play.example.com/"},
+			want:    http.StatusSwitchingProtocols,
+		},
+		{
+			name:    "any origin is let in with a wildcard",
+			origin:  "https://evil.example.com",
+			allowed: []string{"*"},
+			want:    http.StatusSwitchingProtocols,
+		},
+		{
+			name:    "other origins are turned away",
+			origin:  "https://evil.example.com",
+			allowed: []string{"https://play.example.com"},
+			want:    http.StatusForbidden,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				conn, err := Upgrade(w, r, c.allowed)
+				if err == nil {
+					conn.Close()
+				}
+			}))
+			defer server.Close()
+
+			conn, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+			require.NoError(t, err)
+			defer conn.Close()
+
+			request := "GET / HTTP/1.1\r\n" +
+				"Host: localhost\r\n" +
+				"Upgrade: websocket\r\n" +
+				"Connection: Upgrade\r\n" +
+				"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n" +
+				"Sec-WebSocket-Version: 13\r\n"
+			if c.origin != "" {
+				request += "Origin: " + c.origin + "\r\n"
+			}
+			_, err = conn.Write([]byte(request + "\r\n"))
+			require.NoError(t, err)
+
+			resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+			require.NoError(t, err)
+			resp.Body.Close()
+			require.Equal(t, c.want, resp.StatusCode)
+		})
+	}
+}
+
+func TestConn_BadControlFrames(t *testing.T) {
+	t.Parallel()
+
+	type tc struct {
+		name  string
+		frame []byte
+	}
+
+	cases := []tc{
+		{
+			name:  "fragmented ping",
+			frame: clientFrame(false, OpPing, []byte("hi")),
+		},
+		{
+			name:  "ping longer than 125 bytes",
+			frame: clientFrame(true, OpPing, []byte(strings.Repeat("a", 126))),
+		},
+		{
+			name:  "fragmented close",
+			frame: clientFrame(false, OpClose, binary.BigEndian.AppendUint16(nil, CloseNormal)),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := make(chan error, 1)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				conn, err := Upgrade(w, r, nil)
+				if err != nil {
+					return
+				}
+				_, err = conn.ReadMessage()
+				result <- err
+			}))
+			defer server.Close()
+
+			conn, reader := dial(t, server)
+			defer conn.Close()
+
+			_, err := conn.Write(c.frame)
+			require.NoError(t, err)
+
+			require.EqualError(t, <-result, "websocket: control frame is fragmented or too long")
+
+			opcode, payload := readServerFrame(t, reader)
+			require.Equal(t, OpClose, opcode)
+			require.Equal(t, uint16(CloseProtocolError), binary.BigEndian.Uint16(payload))
+		})
+	}
+}
+
+func TestConn_Messages(t *testing.T) {
+	t.Parallel()
+
+	type tc struct {
+		name   string
+		frames [][]byte
+		want   []byte
+	}
+
+	cases := []tc{
+		{
+			name:   "single text frame",
+			frames: [][]byte{clientFrame(true, OpText, []byte("look"))},
+			want:   []byte("look"),
+		},
+		{
+			name: "fragments are assembled",
+			frames: [][]byte{
+				clientFrame(false, OpText, []byte("lo")),
+				clientFrame(true, OpContinuation, []byte("ok")),
+			},
+			want: []byte("look"),
+		},
+		{
+			name: "ping between fragments is answered",
+			frames: [][]byte{
+				clientFrame(false, OpText, []byte("lo")),
+				clientFrame(true, OpPing, []byte("hi")),
+				clientFrame(true, OpContinuation, []byte("ok")),
+			},
+			want: []byte("look"),
+		},
+		{
+			name:   "extended length",
+			frames: [][]byte{clientFrame(true, OpText, []byte(strings.Repeat("a", 300)))},
+			want:   []byte(strings.Repeat("a", 300)),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			received := make(chan []byte, 1)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				conn, err := Upgrade(w, r, nil)
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+
+				msg, err := conn.ReadMessage()
+				if err != nil {
+					close(received)
+					return
+				}
+				received <- msg
+				conn.WriteMessage(append([]byte("echo "), msg...))
+			}))
+			defer server.Close()
+
+			conn, reader := dial(t, server)
+			defer conn.Close()
+
+			for _, f := range c.frames {
+				_, err := conn.Write(f)
+				require.NoError(t, err)
+			}
+
+			require.Equal(t, c.want, <-received)
+
+			// pongs come back before the echo
+			for {
+				opcode, payload := readServerFrame(t, reader)
+				if opcode == OpPong {
+					require.Equal(t, []byte("hi"), payload)
+					continue
+				}
+				require.Equal(t, OpText, opcode)
+				require.Equal(t, append([]byte("echo "), c.want...), payload)
+				break
+			}
+
+			opcode, payload := readServerFrame(t, reader)
+			require.Equal(t, OpClose, opcode)
+			require.Equal(t, uint16(CloseNormal), binary.BigEndian.Uint16(payload))
+		})
+	}
+}
+
+func TestConn_CloseFromClient(t *testing.T) {
+	t.Parallel()
+
+	result := make(chan error, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		_, err = conn.ReadMessage()
+		result <- err
+	}))
+	defer server.Close()
+
+	conn, reader := dial(t, server)
+	defer conn.Close()
+
+	_, err := conn.Write(clientFrame(true, OpClose, binary.BigEndian.AppendUint16(nil, CloseNormal)))
+	require.NoError(t, err)
+
+	require.ErrorIs(t, <-result, io.EOF)
+
+	opcode, _ := readServerFrame(t, reader)
+	require.Equal(t, OpClose, opcode)
+}
+
+func dial(t *testing.T, server *httptest.Server) (net.Conn, *bufio.Reader) {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+	require.NoError(t, err)
+
+	key := "dGhlIHNhbXBsZSBub25jZQ=="
+	_, err = conn.Write([]byte("GET / HTTP/1.1\r\n" +
+		"Host: localhost\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: keep-alive, Upgrade\r\n" +
+		"Sec-WebSocket-Key: " + key + "\r\n" +
+		"Sec-WebSocket-Version: 13\r\n\r\n"))
+	require.NoError(t, err)
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+	require.Equal(t, AcceptKey(key), resp.Header.Get("Sec-WebSocket-Accept"))
+
+	return conn, reader
+}
+
+func clientFrame(fin bool, opcode byte, payload []byte) []byte {
+	first := opcode
+	if fin {
+		first |= 0x80
+	}
+	frame := []byte{first}
+
+	switch n := len(payload); {
+	case n < 126:
+		frame = append(frame, 0x80|byte(n))
+	default:
+		frame = append(frame, 0x80|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	}
+
+	mask := []byte{1, 2, 3, 4}
+	frame = append(frame, mask...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	return frame
+}
+
+func readServerFrame(t *testing.T, r *bufio.Reader) (byte, []byte) {
+	t.Helper()
+
+	var header [2]byte
+	_, err := io.ReadFull(r, header[:])
+	require.NoError(t, err)
+	require.Zero(t, header[1]&0x80, "server frames must not be masked")
+
+	length := int(header[1] & 0x7F)
+	if length == 126 {
+		var ext [2]byte
+		_, err := io.ReadFull(r, ext[:])
+		require.NoError(t, err)
+		length = int(binary.BigEndian.Uint16(ext[:]))
+	}
+
+	payload := make([]byte, length)
+	_, err = io.ReadFull(r, payload)
+	require.NoError(t, err)
+
+	return header[0] & 0x0F, payload
+}
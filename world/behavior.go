@@ -0,0 +1,169 @@
+package world
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/components"
+	"example.com/mud/world/player"
+	"example.com/mud/world/scheduler"
+)
+
+// how many commands performed or moves made by entities may set off one
+// another, e.g. NPCs following each other around, before giving up
+const maxPerformDepth = 8
+
+// Perform runs line as a command typed by actor, through the same parser and
+// commands as players. It must be called from the world's loop.
+func (w *World) Perform(actor *entities.Entity, line string) error {
+	if w.performing >= maxPerformDepth {
+		return fmt.Errorf("perform '%s' for '%s': too many commands performed in a row", line, actor.Name)
+	}
+	w.performing++
+	defer func() { w.performing-- }()
+
+	// players made to do something act as themselves
+	p := w.playerFor(actor)
+	if p == nil {
+		room := w.roomOf(actor)
+		if room == nil {
+			return fmt.Errorf("perform '%s' for '%s': not in a room", line, actor.Name)
+		}
+		p = player.NewActor(actor, w, room)
+	}
+
+	_, err := w.parse(p, line)
+	var amb *entities.AmbiguityError
+	if errors.As(err, &amb) {
+		// there's nobody to choose, so ambiguous commands do nothing
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("perform '%s' for '%s': %w", line, actor.Name, err)
+	}
+
+	return nil
+}
+
+// startAllBehaviors starts every entity in the world with a Behavior ticking.
+func (w *World) startAllBehaviors() {
+	for _, e := range w.entityMap {
+		w.startBehaviors(e)
+	}
+}
+
+// startBehaviors starts e and everything within it ticking, if they have a
+// Behavior and aren't already.
+func (w *World) startBehaviors(e *entities.Entity) {
+	if b, ok := entities.GetComponent[*components.Behavior](e); ok && inWorld(e) {
+		w.startTicking(e, b)
+	}
+
+	for _, cwc := range e.GetComponentsWithChildren() {
+		for _, child := range cwc.GetChildren().GetChildren() {
+			w.startBehaviors(child)
+		}
+	}
+}
+
+func (w *World) startTicking(e *entities.Entity, b *components.Behavior) {
+	if b.Tick <= 0 || b.Ticking() {
+		return
+	}
+
+	// the job belongs to the entity, so it stops when the entity is destroyed
+	job := &scheduler.Job{
+		NextRun: w.Scheduler.Now().Add(b.Tick),
+		Label:   entities.EventTick,
+		Owner:   e,
+	}
+	job.RunFunc = func() {
+		if !inWorld(e) || b.Tick <= 0 {
+			b.StopTicking()
+			return
+		}
+
+		w.tick(e, b)
+
+		job.NextRun = job.NextRun.Add(b.Tick)
+		w.Scheduler.Add(job)
+	}
+
+	b.StartTicking(job)
+	w.Scheduler.Add(job)
+}
+
+func (w *World) tick(e *entities.Entity, b *components.Behavior) {
+	room := w.roomOf(e)
+
+	w.sendEvent(e, &entities.Event{
+		Type:   entities.EventTick,
+		Room:   room,
+		Source: e,
+		Target: e,
+	})
+
+	// only entities standing in a room wander, not what's in a bag
+	if b.Wander == 0 || room == nil || room == e || rand.Intn(100) >= b.Wander {
+		return
+	}
+	if _, ok := e.Parent.(*components.Room); !ok {
+		return
+	}
+
+	rm, err := entities.RequireComponent[*components.Room](room)
+	if err != nil {
+		return
+	}
+
+	// wanderers don't open doors
+	exits := make([]string, 0, len(rm.Exits))
+	for _, exit := range rm.VisibleExits() {
+		if exit.Open() {
+			exits = append(exits, exit.Direction)
+		}
+	}
+	if len(exits) == 0 {
+		return
+	}
+
+	if _, err := w.MoveEntity(e, exits[rand.Intn(len(exits))]); err != nil {
+		log.Printf("wander: %v", err)
+	}
+}
+
+// roomOf finds the room e is standing in, or e itself if it is a room. Things
+// held by something else aren't in a room.
+func (w *World) roomOf(e *entities.Entity) *entities.Entity {
+	if _, ok := entities.GetComponent[*components.Room](e); ok {
+		return e
+	}
+
+	parent, ok := e.Parent.(*components.Room)
+	if !ok {
+		return nil
+	}
+	return parent.Owner()
+}
+
+// playerFor finds the connected player controlling e.
+func (w *World) playerFor(e *entities.Entity) *player.Player {
+	for _, p := range w.players {
+		if p.Entity == e {
+			return p
+		}
+	}
+	return nil
+}
+
+// inWorld reports whether e has been placed in the world, rather than being a
+// prototype or having been removed. Rooms are always in the world.
+func inWorld(e *entities.Entity) bool {
+	if _, ok := entities.GetComponent[*components.Room](e); ok {
+		return true
+	}
+	return e.Parent != nil
+}
@@ -113,3 +113,23 @@ func (b *Bus) PublishTo(room *entities.Entity, recipient *entities.Entity, text
 		// drop if receiver is slow
 	}
 }
+
+// Broadcast sends text to every subscriber, in every room.
+func (b *Bus) Broadcast(text string) {
+	b.mu.RLock()
+	var targets []chan string
+	for _, subscribers := range b.roomSubscribers {
+		for _, inbox := range subscribers {
+			targets = append(targets, inbox)
+		}
+	}
+	b.mu.RUnlock()
+
+	for _, inbox := range targets {
+		select {
+		case inbox <- text:
+		default:
+			// drop if receiver is slow
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package world
+
+import (
+	"fmt"
+	"strings"
+
+	"example.com/mud/parser/commands"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/components"
+	"example.com/mud/world/player"
+)
+
+// doorCommand opens, closes, locks or unlocks the door target names in p's
+// room, e.g. "north door", or just "door" if there is only one. It reports
+// false if there is no such door, so entities can react instead.
+func (w *World) doorCommand(p *player.Player, kind, target, instrument string) (string, bool, error) {
+	switch kind {
+	case "open", "close", "lock", "unlock":
+	default:
+		return "", false, nil
+	}
+
+	room, ok := entities.GetComponent[*components.Room](p.CurrentRoom)
+	if !ok {
+		return "", false, nil
+	}
+
+	door := findDoor(room, target)
+	if door == nil {
+		return "", false, nil
+	}
+
+	var reply string
+	var err error
+	switch kind {
+	case "open":
+		reply = w.openDoor(p, door)
+	case "close":
+		reply = w.closeDoor(p, door)
+	case "lock":
+		reply, err = w.lockDoor(p, door, instrument, true)
+	case "unlock":
+		reply, err = w.lockDoor(p, door, instrument, false)
+	}
+
+	return reply, true, err
+}
+
+func findDoor(room *components.Room, target string) *components.Exit {
+	name := strings.TrimSpace(strings.TrimSuffix(target, "door"))
+
+	// "door" alone is fine while there is only one
+	if name == "" {
+		var found *components.Exit
+		for _, exit := range room.VisibleExits() {
+			if !exit.Door {
+				continue
+			}
+			if found != nil {
+				return nil
+			}
+			found = exit
+		}
+		return found
+	}
+
+	name = commands.CanonicalDirection(name)
+
+	exit, ok := room.GetExit(name)
+	if !ok || !exit.Door {
+		return nil
+	}
+	return exit
+}
+
+func (w *World) openDoor(p *player.Player, door *components.Exit) string {
+	if !door.Closed {
+		return fmt.Sprintf("The %s is already open.", door.Name())
+	}
+	if door.Locked {
+		return fmt.Sprintf("The %s is locked.", door.Name())
+	}
+
+	door.Closed = false
+	w.updateOtherSide(p.CurrentRoom, door, "opens")
+
+	w.Publish(p.CurrentRoom, fmt.Sprintf("%s opens the %s.", p.Name, door.Name()), []*entities.Entity{p.Entity})
+	return fmt.Sprintf("You open the %s.", door.Name())
+}
+
+func (w *World) closeDoor(p *player.Player, door *components.Exit) string {
+	if door.Closed {
+		return fmt.Sprintf("The %s is already closed.", door.Name())
+	}
+
+	door.Closed = true
+	w.updateOtherSide(p.CurrentRoom, door, "closes")
+
+	w.Publish(p.CurrentRoom, fmt.Sprintf("%s closes the %s.", p.Name, door.Name()), []*entities.Entity{p.Entity})
+	return fmt.Sprintf("You close the %s.", door.Name())
+}
+
+func (w *World) lockDoor(p *player.Player, door *components.Exit, instrument string, lock bool) (string, error) {
+	verb := "lock"
+	if !lock {
+		verb = "unlock"
+	}
+
+	switch {
+	case door.KeyId == "":
+		return fmt.Sprintf("The %s has no lock.", door.Name()), nil
+	case lock && door.Locked:
+		return fmt.Sprintf("The %s is already locked.", door.Name()), nil
+	case !lock && !door.Locked:
+		return fmt.Sprintf("The %s isn't locked.", door.Name()), nil
+	case lock && !door.Closed:
+		return fmt.Sprintf("You need to close the %s first.", door.Name()), nil
+	}
+
+	if refusal := findKey(p, door, instrument); refusal != "" {
+		return refusal, nil
+	}
+
+	door.Locked = lock
+	w.updateOtherSide(p.CurrentRoom, door, "clicks")
+
+	w.Publish(p.CurrentRoom, fmt.Sprintf("%s %ss the %s.", p.Name, verb, door.Name()), []*entities.Entity{p.Entity})
+	return fmt.Sprintf("You %s the %s.", verb, door.Name()), nil
+}
+
+// findKey explains why p can't lock or unlock door, or returns nothing if
+// they are holding its key. instrument names the key to use, if given.
+func findKey(p *player.Player, door *components.Exit, instrument string) string {
+	inventory, ok := entities.GetComponent[*components.Inventory](p.Entity)
+	if !ok {
+		return "You don't have the key."
+	}
+
+	if instrument == "" {
+		for _, child := range inventory.GetChildren().GetChildren() {
+			if child.Id == door.KeyId {
+				return ""
+			}
+		}
+		return "You don't have the key."
+	}
+
+	matches := inventory.GetChildren().GetChildrenByAlias(instrument)
+	if len(matches) == 0 {
+		return fmt.Sprintf("You don't have %s.", instrument)
+	}
+	for _, match := range matches {
+		if match.Entity.Id == door.KeyId {
+			return ""
+		}
+	}
+	return fmt.Sprintf("The %s doesn't fit the lock.", instrument)
+}
+
+// updateOtherSide makes the door leading back from where door goes match it,
+// since they are the same door, and tells anyone on the other side. One-way
+// doors have no other side, any door back is a door of its own.
+func (w *World) updateOtherSide(room *entities.Entity, door *components.Exit, happened string) {
+	if door.OneWay {
+		return
+	}
+
+	otherRoom, ok := w.entityMap[door.RoomId]
+	if !ok {
+		return
+	}
+	other, ok := entities.GetComponent[*components.Room](otherRoom)
+	if !ok {
+		return
+	}
+
+	for _, exit := range other.Exits {
+		if exit.RoomId != room.Id || !exit.Door || exit.OneWay {
+			continue
+		}
+
+		exit.Closed = door.Closed
+		exit.Locked = door.Locked
+		if !exit.Hidden {
+			w.Publish(otherRoom, fmt.Sprintf("The %s %s.", exit.Name(), happened), nil)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package world_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"example.com/mud/world"
+	"example.com/mud/world/worldtest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorld_Doors(t *testing.T) {
+	dir := filepath.Join("testdata", "doors")
+
+	w, err := worldtest.FromDirectory(dir, "Hall")
+	require.NoError(t, err)
+	worldtest.RunTranscript(t, w, filepath.Join(dir, "doors.txt"))
+
+	var snapshot *world.Snapshot
+	w.Do(func() {
+		snapshot = w.Snapshot()
+	})
+
+	after, err := worldtest.FromDirectory(dir, "Hall")
+	require.NoError(t, err)
+	require.NoError(t, after.Restore(snapshot))
+	worldtest.RunTranscript(t, after, filepath.Join(dir, "restored.txt"))
+}
+
+func TestWorld_OneWayDoors(t *testing.T) {
+	worldtest.RunTranscripts(t, filepath.Join("testdata", "one_way"), "Hall")
+}
@@ -1,5 +1,28 @@
 package entities
 
+import "time"
+
 type Action interface {
 	Execute(ev *Event) error
 }
+
+// Schedule is an action running others later. The jobs it adds can be saved
+// with the world, and scheduled again once it is restored.
+type Schedule interface {
+	Action
+	// Key tells the action apart from every other in the definitions
+	Key() string
+	// Name is the label given to the jobs it adds
+	Name() string
+	// Every is how often the job repeats, or 0 if it only runs once
+	Every() time.Duration
+	// ScheduleAt adds the job for ev, first running at next
+	ScheduleAt(ev *Event, next time.Time)
+}
+
+// ScheduledJob is attached to jobs added by a Schedule, describing them so
+// they can be saved.
+type ScheduledJob struct {
+	Schedule Schedule
+	Event    *Event
+}
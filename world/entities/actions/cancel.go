@@ -0,0 +1,26 @@
+package actions
+
+import (
+	"fmt"
+
+	"example.com/mud/world/entities"
+)
+
+// Cancel stops jobs scheduled by an entity, only those with Label unless it
+// is empty.
+type Cancel struct {
+	Role  entities.EventRole
+	Label string
+}
+
+var _ entities.Action = &Cancel{}
+
+func (c *Cancel) Execute(ev *entities.Event) error {
+	owner, err := ev.GetRole(c.Role)
+	if err != nil {
+		return fmt.Errorf("cancel action: %w", err)
+	}
+
+	ev.Scheduler.CancelOwnedBy(owner, c.Label)
+	return nil
+}
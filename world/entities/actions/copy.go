@@ -35,9 +35,12 @@ func (c *Copy) Execute(ev *entities.Event) error {
 		return fmt.Errorf("Copy execute: entity '%s' doesn't exist", c.EntityId)
 	}
 
-	component.AddChild(
-		entityToCopy.Copy(component),
-	)
+	copied := entityToCopy.Copy(component)
+	component.AddChild(copied)
+
+	if ev.World != nil {
+		ev.World.Spawned(copied)
+	}
 
 	return nil
 }
@@ -32,8 +32,29 @@ func (d *Destroy) Execute(ev *entities.Event) error {
 		return fmt.Errorf("role '%s' is empty for destroy event", d.Role)
 	}
 
+	if ev.World != nil {
+		ev.World.Destroying(role)
+	}
+
 	// remove role from parent (is this enough for garbage collection to kick in?)
-	role.Parent.RemoveChild(role)
+	// reactions to it being destroyed may have already
+	if role.Parent != nil {
+		role.Parent.RemoveChild(role)
+	}
+
+	// nothing destroyed should go on acting
+	cancelJobs(ev.Scheduler, role)
 
 	return nil
 }
+
+// cancel every job owned by entity, or anything it holds
+func cancelJobs(s entities.Scheduler, entity *entities.Entity) {
+	s.CancelOwnedBy(entity, "")
+
+	for _, c := range entity.GetComponentsWithChildren() {
+		for _, child := range c.GetChildren().GetChildren() {
+			cancelJobs(s, child)
+		}
+	}
+}
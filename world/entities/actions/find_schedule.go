@@ -0,0 +1,83 @@
+package actions
+
+import (
+	"fmt"
+
+	"example.com/mud/world/entities"
+)
+
+// NumberSchedules gives every Schedule within rules, the reactions of the
+// entity with id, a Ref made of the verb it reacts to and the index of each
+// rule and action leading to it, e.g. "Bell:ring/0/1". Unlike where it is
+// written, this only changes when the reaction holding it does.
+func NumberSchedules(id string, rules map[string][]*entities.Rule) {
+	for verb, rs := range rules {
+		numberRules(rs, fmt.Sprintf("%s:%s", id, verb))
+	}
+}
+
+func numberRules(rules []*entities.Rule, ref string) {
+	for i, r := range rules {
+		numberActions(r.Then, fmt.Sprintf("%s/%d", ref, i))
+	}
+}
+
+func numberActions(as []entities.Action, ref string) {
+	for i, a := range as {
+		ref := fmt.Sprintf("%s/%d", ref, i)
+
+		switch a := a.(type) {
+		case *Conditional:
+			numberRules(a.RuleChain, ref)
+		case *ScheduleOnce:
+			a.Ref = ref
+			numberActions(a.Actions, ref)
+		case *ScheduleRepeating:
+			a.Ref = ref
+			numberRules([]*entities.Rule{a.Rule}, ref)
+		}
+	}
+}
+
+// FindSchedule looks through rules, and every action nested within them, for
+// the Schedule with key.
+func FindSchedule(rules map[string][]*entities.Rule, key string) (entities.Schedule, bool) {
+	for _, rs := range rules {
+		if s, ok := findScheduleInRules(rs, key); ok {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+func findScheduleInRules(rules []*entities.Rule, key string) (entities.Schedule, bool) {
+	for _, r := range rules {
+		if s, ok := findSchedule(r.Then, key); ok {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+func findSchedule(as []entities.Action, key string) (entities.Schedule, bool) {
+	for _, a := range as {
+		if s, ok := a.(entities.Schedule); ok && s.Key() == key {
+			return s, true
+		}
+
+		var nested entities.Schedule
+		var found bool
+		switch a := a.(type) {
+		case *Conditional:
+			nested, found = findScheduleInRules(a.RuleChain, key)
+		case *ScheduleOnce:
+			nested, found = findSchedule(a.Actions, key)
+		case *ScheduleRepeating:
+			nested, found = findScheduleInRules([]*entities.Rule{a.Rule}, key)
+		}
+		if found {
+			return nested, true
+		}
+	}
+	return nil, false
+}
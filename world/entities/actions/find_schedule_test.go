@@ -0,0 +1,76 @@
+package actions
+
+import (
+	"testing"
+
+	"example.com/mud/world/entities"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNumberSchedules(t *testing.T) {
+	t.Parallel()
+
+	inner := &ScheduleOnce{Label: "in 1 seconds"}
+	repeating := &ScheduleRepeating{
+		Label: "every 2 seconds",
+		Rule:  &entities.Rule{Then: []entities.Action{&Publish{}, inner}},
+	}
+	once := &ScheduleOnce{Label: "nap"}
+	conditional := &Conditional{
+		RuleChain: []*entities.Rule{
+			{Then: []entities.Action{&Publish{}}},
+			{Then: []entities.Action{once}},
+		},
+	}
+
+	rules := map[string][]*entities.Rule{
+		"poke": {
+			{Then: []entities.Action{&Publish{}}},
+			{Then: []entities.Action{&Publish{}, repeating}},
+		},
+		"ring": {
+			{Then: []entities.Action{conditional}},
+		},
+	}
+
+	NumberSchedules("Bell", rules)
+
+	type tc struct {
+		name     string
+		schedule entities.Schedule
+		want     string
+	}
+
+	cases := []tc{
+		{
+			name:     "in a reaction",
+			schedule: repeating,
+			want:     "Bell:poke/1/1",
+		},
+		{
+			name:     "in a repeating schedule",
+			schedule: inner,
+			want:     "Bell:poke/1/1/0/1",
+		},
+		{
+			name:     "in a conditional",
+			schedule: once,
+			want:     "Bell:ring/0/0/1/0",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, c.want, c.schedule.Key())
+
+			found, ok := FindSchedule(rules, c.want)
+			require.True(t, ok)
+			require.Same(t, c.schedule, found)
+		})
+	}
+
+	_, ok := FindSchedule(rules, "Bell:poke/0/0")
+	require.False(t, ok)
+}
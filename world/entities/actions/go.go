@@ -0,0 +1,38 @@
+package actions
+
+import (
+	"fmt"
+
+	"example.com/mud/world/entities"
+)
+
+// Go takes an entity through an exit of its room, just as if it walked. If the
+// way is shut, it stays where it is.
+type Go struct {
+	Role      entities.EventRole
+	Direction string
+}
+
+var _ entities.Action = &Go{}
+
+func (g *Go) Execute(ev *entities.Event) error {
+	if ev.World == nil {
+		return fmt.Errorf("world in event may not be nil for go action")
+	}
+
+	mover, err := ev.GetRole(g.Role)
+	if err != nil {
+		return fmt.Errorf("go execute: %w", err)
+	}
+
+	direction, err := entities.FormatEventMessage(g.Direction, ev)
+	if err != nil {
+		return err
+	}
+
+	if _, err := ev.World.MoveEntity(mover, direction); err != nil {
+		return fmt.Errorf("go execute: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package actions
+
+import (
+	"fmt"
+
+	"example.com/mud/world/entities"
+)
+
+// Perform has an entity run a command, just as if a player had typed it.
+type Perform struct {
+	Role entities.EventRole
+	Line string
+}
+
+var _ entities.Action = &Perform{}
+
+func (p *Perform) Execute(ev *entities.Event) error {
+	if ev.World == nil {
+		return fmt.Errorf("world in event may not be nil for perform action")
+	}
+
+	actor, err := ev.GetRole(p.Role)
+	if err != nil {
+		return fmt.Errorf("perform execute: %w", err)
+	}
+
+	line, err := entities.FormatEventMessage(p.Line, ev)
+	if err != nil {
+		return err
+	}
+
+	return ev.World.Perform(actor, line)
+}
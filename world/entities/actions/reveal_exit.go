@@ -0,0 +1,38 @@
+package actions
+
+import (
+	"fmt"
+
+	"example.com/mud/parser/commands"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/components"
+)
+
+// RevealExit shows or hides an exit of the room the event happens in.
+type RevealExit struct {
+	Direction string
+	Reveal    bool
+}
+
+var _ entities.Action = &RevealExit{}
+
+func (r *RevealExit) Execute(ev *entities.Event) error {
+	if ev.Room == nil {
+		return fmt.Errorf("room is empty for reveal exit event")
+	}
+
+	room, err := entities.RequireComponent[*components.Room](ev.Room)
+	if err != nil {
+		return fmt.Errorf("error executing reveal exit action: %w", err)
+	}
+
+	// exits are found the way players name them, e.g. "n" for "north"
+	exit, ok := room.FindExit(commands.CanonicalDirection(r.Direction))
+	if !ok {
+		return fmt.Errorf("error executing reveal exit action: room '%s' has no exit '%s'", ev.Room.Name, r.Direction)
+	}
+
+	exit.Hidden = !r.Reveal
+
+	return nil
+}
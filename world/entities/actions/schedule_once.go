@@ -10,13 +10,38 @@ import (
 type ScheduleOnce struct {
 	Nanoseconds time.Duration
 	Actions     []entities.Action
+	// names the job, so it can be cancelled
+	Label string
+	// where the action sits among its entity's reactions, e.g. "Bell:ring/0/1",
+	// so saved jobs can find it again, see NumberSchedules
+	Ref string
 }
 
-var _ entities.Action = &ScheduleOnce{}
+var _ entities.Schedule = &ScheduleOnce{}
 
 func (c *ScheduleOnce) Execute(ev *entities.Event) error {
+	c.ScheduleAt(ev, ev.Scheduler.Now().Add(c.Nanoseconds))
+	return nil
+}
+
+func (c *ScheduleOnce) Key() string {
+	return c.Ref
+}
+
+func (c *ScheduleOnce) Name() string {
+	return c.Label
+}
+
+func (c *ScheduleOnce) Every() time.Duration {
+	return 0
+}
+
+func (c *ScheduleOnce) ScheduleAt(ev *entities.Event, next time.Time) {
 	ev.Scheduler.Add(&scheduler.Job{
-		NextRun: time.Now().Add(c.Nanoseconds),
+		NextRun: next,
+		Label:   c.Label,
+		Owner:   jobOwner(ev),
+		Data:    &entities.ScheduledJob{Schedule: c, Event: ev},
 		RunFunc: func() {
 			for _, a := range c.Actions {
 				err := a.Execute(ev)
@@ -26,6 +51,13 @@ func (c *ScheduleOnce) Execute(ev *entities.Event) error {
 			}
 		},
 	})
+}
 
+// the owner of jobs scheduled by ev, left nil rather than a nil entity so
+// ownerless jobs aren't all owned by the same thing
+func jobOwner(ev *entities.Event) any {
+	if owner := ev.Owner(); owner != nil {
+		return owner
+	}
 	return nil
 }
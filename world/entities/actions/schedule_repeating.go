@@ -10,40 +10,63 @@ import (
 type ScheduleRepeating struct {
 	Nanoseconds time.Duration
 	Rule        *entities.Rule
+	// names the job, so it can be cancelled
+	Label string
+	// where the action sits among its entity's reactions, e.g. "Bell:ring/0/1",
+	// so saved jobs can find it again, see NumberSchedules
+	Ref string
 }
 
-var _ entities.Action = &ScheduleRepeating{}
+var _ entities.Schedule = &ScheduleRepeating{}
 
 func (sr *ScheduleRepeating) Execute(ev *entities.Event) error {
-	// defining a schedule function before instantiating it allows recursion inside the schedule function
-	var schedule func(next time.Time)
-
-	schedule = func(next time.Time) {
-		ev.Scheduler.Add(&scheduler.Job{
-			NextRun: next,
-			RunFunc: func() {
-				for _, condition := range sr.Rule.When {
-					ok, err := condition.Check(ev)
-					if err != nil || !ok {
-						// stop rescheduling if any condition fails or errors
-						return
-					}
-				}
-
-				for _, action := range sr.Rule.Then {
-					if err := action.Execute(ev); err != nil {
-						// stop on first action error
-						return
-					}
-				}
-
-				// if we reach this far, reschedule again.
-				schedule(next.Add(sr.Nanoseconds))
-			},
-		})
-	}
-
 	// kick off the first run
-	schedule(time.Now().Add(sr.Nanoseconds))
+	sr.ScheduleAt(ev, ev.Scheduler.Now().Add(sr.Nanoseconds))
 	return nil
 }
+
+func (sr *ScheduleRepeating) Key() string {
+	return sr.Ref
+}
+
+func (sr *ScheduleRepeating) Name() string {
+	return sr.Label
+}
+
+func (sr *ScheduleRepeating) Every() time.Duration {
+	return sr.Nanoseconds
+}
+
+func (sr *ScheduleRepeating) ScheduleAt(ev *entities.Event, next time.Time) {
+	// the same job is added again after each run, so it keeps its id and
+	// cancelling it stops every later run
+	job := &scheduler.Job{
+		NextRun: next,
+		Label:   sr.Label,
+		Owner:   jobOwner(ev),
+		Data:    &entities.ScheduledJob{Schedule: sr, Event: ev},
+	}
+
+	job.RunFunc = func() {
+		for _, condition := range sr.Rule.When {
+			ok, err := condition.Check(ev)
+			if err != nil || !ok {
+				// stop rescheduling if any condition fails or errors
+				return
+			}
+		}
+
+		for _, action := range sr.Rule.Then {
+			if err := action.Execute(ev); err != nil {
+				// stop on first action error
+				return
+			}
+		}
+
+		// if we reach this far, reschedule again.
+		job.NextRun = job.NextRun.Add(sr.Nanoseconds)
+		ev.Scheduler.Add(job)
+	}
+
+	ev.Scheduler.Add(job)
+}
@@ -26,19 +26,12 @@ func TestScheduleRepeating_Execute(t *testing.T) {
 		build func(t *testing.T) (fields, *entities.Event, func(t *testing.T))
 	}
 
-	const tolerance = 5 * time.Second
+	start := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
 
-	newScheduler := func(t *testing.T, captured *[]*scheduler.Job) *mocks.MockScheduler {
+	newScheduler := func(t *testing.T) (*scheduler.Scheduler, *scheduler.FakeClock) {
 		t.Helper()
-		ms := new(mocks.MockScheduler)
-		ms.
-			On("Add", mock.MatchedBy(func(j *scheduler.Job) bool {
-				*captured = append(*captured, j)
-				return j != nil
-			})).
-			Return().
-			Maybe()
-		return ms
+		clock := scheduler.NewFakeClock(start)
+		return scheduler.NewSchedulerWithClock(clock), clock
 	}
 	newCond := func(t *testing.T) *mocks.MockCondition {
 		t.Helper()
@@ -53,46 +46,38 @@ func TestScheduleRepeating_Execute(t *testing.T) {
 		{
 			name: "repeats when all conditions true and actions succeed",
 			build: func(t *testing.T) (fields, *entities.Event, func(t *testing.T)) {
-				var jobs []*scheduler.Job
-				mSched := newScheduler(t, &jobs)
+				sched, clock := newScheduler(t)
 
 				delay := 25 * time.Millisecond
-				start := time.Now()
 
 				c1 := newCond(t)
 				a1 := newAction(t)
 				a2 := newAction(t)
 
-				ev := &entities.Event{Scheduler: mSched}
+				ev := &entities.Event{Scheduler: sched}
 
-				c1.On("Check", ev).Return(true, nil).Once()
-				a1.On("Execute", ev).Return(nil).Once()
-				a2.On("Execute", ev).Return(nil).Once()
-
-				c1.On("Check", ev).Return(true, nil).Once()
-				a1.On("Execute", ev).Return(nil).Once()
-				a2.On("Execute", ev).Return(nil).Once()
+				var runs []time.Time
+				c1.On("Check", ev).Return(true, nil).Times(2)
+				a1.On("Execute", ev).Return(nil).Times(2).Run(func(_ mock.Arguments) {
+					runs = append(runs, clock.Now())
+				})
+				a2.On("Execute", ev).Return(nil).Times(2)
 
 				rule := &entities.Rule{When: []entities.Condition{c1}, Then: []entities.Action{a1, a2}}
 
 				verify := func(t *testing.T) {
-					require.GreaterOrEqual(t, len(jobs), 1)
-					first := jobs[0]
-					assert.WithinDuration(t, start.Add(delay), first.NextRun, tolerance)
-
-					first.RunFunc()
+					require.Equal(t, 1, sched.Pending())
 
-					require.GreaterOrEqual(t, len(jobs), 2)
-					second := jobs[1]
-					assert.WithinDuration(t, first.NextRun.Add(delay), second.NextRun, tolerance)
+					// both runs happen when they're due, even advancing past them at once
+					clock.Advance(2*delay + delay/2)
+					assert.Equal(t, []time.Time{start.Add(delay), start.Add(2 * delay)}, runs)
 
-					second.RunFunc()
-					require.GreaterOrEqual(t, len(jobs), 3)
+					// and it's rescheduled again
+					require.Equal(t, 1, sched.Pending())
 
 					c1.AssertExpectations(t)
 					a1.AssertExpectations(t)
 					a2.AssertExpectations(t)
-					mSched.AssertExpectations(t)
 				}
 
 				return fields{delay: delay, rule: rule}, ev, verify
@@ -101,17 +86,15 @@ func TestScheduleRepeating_Execute(t *testing.T) {
 		{
 			name: "does not reschedule when any condition is false",
 			build: func(t *testing.T) (fields, *entities.Event, func(t *testing.T)) {
-				var jobs []*scheduler.Job
-				mSched := newScheduler(t, &jobs)
+				sched, clock := newScheduler(t)
 
 				delay := 10 * time.Millisecond
-				start := time.Now()
 
 				c1 := newCond(t)
 				c2 := newCond(t)
 				a1 := newAction(t)
 
-				ev := &entities.Event{Scheduler: mSched}
+				ev := &entities.Event{Scheduler: sched}
 
 				c1.On("Check", ev).Return(true, nil).Once()
 				c2.On("Check", ev).Return(false, nil).Once()
@@ -119,17 +102,14 @@ func TestScheduleRepeating_Execute(t *testing.T) {
 				rule := &entities.Rule{When: []entities.Condition{c1, c2}, Then: []entities.Action{a1}}
 
 				verify := func(t *testing.T) {
-					require.Equal(t, 1, len(jobs))
-					first := jobs[0]
-					assert.WithinDuration(t, start.Add(delay), first.NextRun, tolerance)
+					require.Equal(t, 1, sched.Pending())
 
-					first.RunFunc()
-					assert.Equal(t, 1, len(jobs))
+					clock.Advance(delay)
+					assert.Equal(t, 0, sched.Pending())
 
 					c1.AssertExpectations(t)
 					c2.AssertExpectations(t)
 					a1.AssertNotCalled(t, "Execute", ev)
-					mSched.AssertExpectations(t)
 				}
 
 				return fields{delay: delay, rule: rule}, ev, verify
@@ -138,27 +118,25 @@ func TestScheduleRepeating_Execute(t *testing.T) {
 		{
 			name: "does not reschedule when a condition errors",
 			build: func(t *testing.T) (fields, *entities.Event, func(t *testing.T)) {
-				var jobs []*scheduler.Job
-				mSched := newScheduler(t, &jobs)
+				sched, clock := newScheduler(t)
 
 				delay := 15 * time.Millisecond
 
 				c1 := newCond(t)
 				a1 := newAction(t)
 
-				ev := &entities.Event{Scheduler: mSched}
+				ev := &entities.Event{Scheduler: sched}
 
 				c1.On("Check", ev).Return(false, errors.New("oops")).Once()
 
 				rule := &entities.Rule{When: []entities.Condition{c1}, Then: []entities.Action{a1}}
 
 				verify := func(t *testing.T) {
-					require.Equal(t, 1, len(jobs))
-					jobs[0].RunFunc()
-					assert.Equal(t, 1, len(jobs))
+					require.Equal(t, 1, sched.Pending())
+					clock.Advance(delay)
+					assert.Equal(t, 0, sched.Pending())
 					a1.AssertNotCalled(t, "Execute", ev)
 					c1.AssertExpectations(t)
-					mSched.AssertExpectations(t)
 				}
 
 				return fields{delay: delay, rule: rule}, ev, verify
@@ -167,8 +145,7 @@ func TestScheduleRepeating_Execute(t *testing.T) {
 		{
 			name: "stops on first action error and does not reschedule",
 			build: func(t *testing.T) (fields, *entities.Event, func(t *testing.T)) {
-				var jobs []*scheduler.Job
-				mSched := newScheduler(t, &jobs)
+				sched, clock := newScheduler(t)
 
 				delay := 20 * time.Millisecond
 
@@ -176,7 +153,7 @@ func TestScheduleRepeating_Execute(t *testing.T) {
 				a1 := newAction(t)
 				a2 := newAction(t)
 
-				ev := &entities.Event{Scheduler: mSched}
+				ev := &entities.Event{Scheduler: sched}
 
 				c1.On("Check", ev).Return(true, nil).Once()
 				a1.On("Execute", ev).Return(errors.New("kaboom")).Once()
@@ -184,14 +161,13 @@ func TestScheduleRepeating_Execute(t *testing.T) {
 				rule := &entities.Rule{When: []entities.Condition{c1}, Then: []entities.Action{a1, a2}}
 
 				verify := func(t *testing.T) {
-					require.Equal(t, 1, len(jobs))
-					jobs[0].RunFunc()
+					require.Equal(t, 1, sched.Pending())
+					clock.Advance(delay)
 
-					assert.Equal(t, 1, len(jobs))
+					assert.Equal(t, 0, sched.Pending())
 					c1.AssertExpectations(t)
 					a1.AssertExpectations(t)
 					a2.AssertNotCalled(t, "Execute", ev)
-					mSched.AssertExpectations(t)
 				}
 
 				return fields{delay: delay, rule: rule}, ev, verify
@@ -200,8 +176,7 @@ func TestScheduleRepeating_Execute(t *testing.T) {
 		{
 			name: "checks all conditions until a failing one, then stops (no actions, no reschedule)",
 			build: func(t *testing.T) (fields, *entities.Event, func(t *testing.T)) {
-				var jobs []*scheduler.Job
-				mSched := newScheduler(t, &jobs)
+				sched, clock := newScheduler(t)
 
 				delay := 30 * time.Millisecond
 
@@ -210,7 +185,7 @@ func TestScheduleRepeating_Execute(t *testing.T) {
 				c3 := newCond(t)
 				a1 := newAction(t)
 
-				ev := &entities.Event{Scheduler: mSched}
+				ev := &entities.Event{Scheduler: sched}
 
 				c1.On("Check", ev).Return(true, nil).Once()
 				c2.On("Check", ev).Return(false, nil).Once()
@@ -218,15 +193,14 @@ func TestScheduleRepeating_Execute(t *testing.T) {
 				rule := &entities.Rule{When: []entities.Condition{c1, c2, c3}, Then: []entities.Action{a1}}
 
 				verify := func(t *testing.T) {
-					require.Equal(t, 1, len(jobs))
-					jobs[0].RunFunc()
+					require.Equal(t, 1, sched.Pending())
+					clock.Advance(delay)
 
-					assert.Equal(t, 1, len(jobs))
+					assert.Equal(t, 0, sched.Pending())
 					c1.AssertExpectations(t)
 					c2.AssertExpectations(t)
 					c3.AssertNotCalled(t, "Check", ev)
 					a1.AssertNotCalled(t, "Execute", ev)
-					mSched.AssertExpectations(t)
 				}
 
 				return fields{delay: delay, rule: rule}, ev, verify
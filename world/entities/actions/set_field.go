@@ -3,13 +3,16 @@ package actions
 import (
 	"fmt"
 
+	"example.com/mud/models"
 	"example.com/mud/world/entities"
 	"example.com/mud/world/entities/expressions"
 )
 
 type SetField struct {
-	Role       entities.EventRole
-	Field      string
+	Role  entities.EventRole
+	Field string
+	// sets a key of a map field rather than the whole field, if given
+	Key        expressions.Expression
 	Expression expressions.Expression
 }
 
@@ -37,5 +40,25 @@ func (sf *SetField) Execute(ev *entities.Event) error {
 		return fmt.Errorf("could not evaluate expression in SetField: %w", err)
 	}
 
-	return e.SetField(sf.Field, exprResult)
+	if sf.Key == nil {
+		return e.SetField(sf.Field, exprResult)
+	}
+
+	key, err := sf.Key.Eval(ev)
+	if err != nil {
+		return fmt.Errorf("could not evaluate key in SetField: %w", err)
+	}
+	if key.K != models.KindString {
+		return fmt.Errorf("key of field '%s' in SetField must be a string", sf.Field)
+	}
+
+	// fields never set start out as empty maps
+	current := e.GetField(sf.Field)
+	switch current.K {
+	case models.KindMap, models.KindNil:
+	default:
+		return fmt.Errorf("field '%s' in SetField is not a map", sf.Field)
+	}
+
+	return e.SetField(sf.Field, current.WithKey(key.S, exprResult))
 }
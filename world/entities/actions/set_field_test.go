@@ -0,0 +1,106 @@
+package actions
+
+import (
+	"testing"
+
+	"example.com/mud/models"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/expressions"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetField_Execute(t *testing.T) {
+	t.Parallel()
+
+	constant := func(v models.Value) expressions.Expression {
+		return &expressions.ExpressionConst{V: v}
+	}
+
+	type tc struct {
+		name      string
+		fields    map[string]models.Value
+		set       SetField
+		want      models.Value
+		errString string
+	}
+
+	cases := []tc{
+		{
+			name: "sets the whole field",
+			set:  SetField{Role: entities.EventRoleSource, Field: "hp", Expression: constant(models.VInt(3))},
+			want: models.VInt(3),
+		},
+		{
+			name: "sets a key of a map field",
+			fields: map[string]models.Value{
+				"stats": models.VMap(map[string]models.Value{"str": models.VInt(1), "dex": models.VInt(2)}),
+			},
+			set: SetField{
+				Role:       entities.EventRoleSource,
+				Field:      "stats",
+				Key:        constant(models.VStr("str")),
+				Expression: constant(models.VInt(5)),
+			},
+			want: models.VMap(map[string]models.Value{"str": models.VInt(5), "dex": models.VInt(2)}),
+		},
+		{
+			name: "fields never set start as empty maps",
+			set: SetField{
+				Role:       entities.EventRoleSource,
+				Field:      "stats",
+				Key:        constant(models.VStr("str")),
+				Expression: constant(models.VInt(5)),
+			},
+			want: models.VMap(map[string]models.Value{"str": models.VInt(5)}),
+		},
+		{
+			name:   "error setting a key of a field which isn't a map",
+			fields: map[string]models.Value{"stats": models.VInt(1)},
+			set: SetField{
+				Role:       entities.EventRoleSource,
+				Field:      "stats",
+				Key:        constant(models.VStr("str")),
+				Expression: constant(models.VInt(5)),
+			},
+			errString: "field 'stats' in SetField is not a map",
+		},
+		{
+			name: "error on a key which isn't a string",
+			set: SetField{
+				Role:       entities.EventRoleSource,
+				Field:      "stats",
+				Key:        constant(models.VInt(1)),
+				Expression: constant(models.VInt(5)),
+			},
+			errString: "key of field 'stats' in SetField must be a string",
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			fields := map[string]models.Value{}
+			for k, v := range c.fields {
+				fields[k] = v
+			}
+			src := entities.NewEntity("source", "desc", []string{"source"}, nil, fields, nil)
+			before := src.GetField(c.set.Field)
+
+			err := c.set.Execute(&entities.Event{Source: src})
+			if c.errString != "" {
+				require.EqualError(t, err, c.errString)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.want, src.GetField(c.set.Field))
+
+			// maps are replaced, never changed where others may share them
+			if before.K == models.KindMap {
+				require.Equal(t, c.fields[c.set.Field], before)
+				require.NotEqual(t, before, src.GetField(c.set.Field))
+			}
+		})
+	}
+}
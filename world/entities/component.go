@@ -12,6 +12,8 @@ const (
 	ComponentEventful
 	ComponentInventory
 	ComponentContainer
+	ComponentGmcp
+	ComponentBehavior
 )
 
 const (
@@ -20,6 +22,8 @@ const (
 	ComponentEventfulString  = "Eventful"
 	ComponentInventoryString = "Inventory"
 	ComponentContainerString = "Container"
+	ComponentGmcpString      = "Gmcp"
+	ComponentBehaviorString  = "Behavior"
 )
 
 func ParseComponentType(s string) (ComponentType, error) {
@@ -32,6 +36,10 @@ func ParseComponentType(s string) (ComponentType, error) {
 		return ComponentInventory, nil
 	case ComponentContainerString:
 		return ComponentContainer, nil
+	case ComponentGmcpString:
+		return ComponentGmcp, nil
+	case ComponentBehaviorString:
+		return ComponentBehavior, nil
 	default:
 		return ComponentUnknown, fmt.Errorf("unknown component type '%s'", s)
 	}
@@ -47,6 +55,10 @@ func (ct ComponentType) String() string {
 		return ComponentInventoryString
 	case ComponentContainer:
 		return ComponentContainerString
+	case ComponentGmcp:
+		return ComponentGmcpString
+	case ComponentBehavior:
+		return ComponentBehaviorString
 	default:
 		return ComponentUnknownString
 	}
@@ -57,6 +69,12 @@ type Component interface {
 	Copy() Component
 }
 
+// ComponentWithOwner is told which entity it was added to, so it can lead
+// back to it.
+type ComponentWithOwner interface {
+	SetOwner(e *Entity)
+}
+
 type ComponentWithChildren interface {
 	AddChild(child *Entity) error
 	RemoveChild(child *Entity)
@@ -0,0 +1,57 @@
+package components
+
+import (
+	"time"
+
+	"example.com/mud/world/entities"
+	"example.com/mud/world/scheduler"
+)
+
+// Behavior lets an entity act on its own. It ticks on a schedule, reacting to
+// tick events, may wander through the exits of its room, and reacts when
+// others enter or leave its room.
+type Behavior struct {
+	// time between ticks, 0 never to tick
+	Tick time.Duration
+	// percent chance of wandering through a random exit each tick
+	Wander int
+
+	// the job ticking the entity, once it is in the world
+	job *scheduler.Job
+}
+
+var _ entities.Component = &Behavior{}
+
+func NewBehavior() *Behavior {
+	return &Behavior{}
+}
+
+func (b *Behavior) Id() entities.ComponentType {
+	return entities.ComponentBehavior
+}
+
+// Copy doesn't copy the ticking job, copies start ticking once in the world.
+func (b *Behavior) Copy() entities.Component {
+	return &Behavior{
+		Tick:   b.Tick,
+		Wander: b.Wander,
+	}
+}
+
+// Ticking reports whether the entity has a tick scheduled.
+func (b *Behavior) Ticking() bool {
+	return b.job != nil && !b.job.Cancelled()
+}
+
+// StartTicking remembers job as the one ticking the entity.
+func (b *Behavior) StartTicking(job *scheduler.Job) {
+	b.job = job
+}
+
+// StopTicking cancels the entity's tick.
+func (b *Behavior) StopTicking() {
+	if b.job != nil {
+		b.job.Cancel()
+		b.job = nil
+	}
+}
@@ -13,6 +13,9 @@ type Children struct {
 	// TODO rename child -> children
 	childByAlias   map[string][]*entities.Entity
 	aliasesByChild map[*entities.Entity][]string
+
+	// children in the order they were added, so they're always listed the same
+	order []*entities.Entity
 }
 
 var _ entities.IChildren = &Children{}
@@ -56,18 +59,38 @@ func (c *Children) AddChild(child *entities.Entity) error {
 	if len(aliases) == 0 {
 		return nil
 	}
-	for _, alias := range aliases {
-		c.aliasesByChild[child] = append(c.aliasesByChild[child], alias)
-		c.childByAlias[alias] = append(c.childByAlias[alias], child)
+	if _, ok := c.aliasesByChild[child]; !ok {
+		c.order = append(c.order, child)
 	}
+	c.indexAliases(child)
 
 	return nil
 }
 
+func (c *Children) indexAliases(child *entities.Entity) {
+	for _, alias := range child.Aliases {
+		c.aliasesByChild[child] = append(c.aliasesByChild[child], alias)
+		c.childByAlias[alias] = append(c.childByAlias[alias], child)
+	}
+}
+
 func (c *Children) RemoveChild(child *entities.Entity) {
+	if !c.unindexAliases(child) {
+		return
+	}
+
+	for i, oc := range c.order {
+		if oc == child {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+}
+
+func (c *Children) unindexAliases(child *entities.Entity) bool {
 	aliases, ok := c.aliasesByChild[child]
 	if !ok {
-		return
+		return false
 	}
 
 	for _, alias := range aliases {
@@ -82,13 +105,12 @@ func (c *Children) RemoveChild(child *entities.Entity) {
 		c.childByAlias[alias] = newEntities
 	}
 	delete(c.aliasesByChild, child) // delete entry from aliasesByItem
+	return true
 }
 
 func (c *Children) GetChildren() []*entities.Entity {
-	children := make([]*entities.Entity, 0)
-	for child := range c.aliasesByChild {
-		children = append(children, child)
-	}
+	children := make([]*entities.Entity, len(c.order))
+	copy(children, c.order)
 	return children
 }
 
@@ -126,11 +148,17 @@ func (c *Children) HasChild(e *entities.Entity) bool {
 }
 
 func (c *Children) ReindexAliasesForEntity(e *entities.Entity) error {
-	c.RemoveChild(e)
-	err := c.AddChild(e)
-	if err != nil {
-		return fmt.Errorf("error reindexing aliases for entity '%s': %w", e.Name, err)
+	if _, ok := c.aliasesByChild[e]; !ok || len(e.Aliases) == 0 {
+		c.RemoveChild(e)
+		err := c.AddChild(e)
+		if err != nil {
+			return fmt.Errorf("error reindexing aliases for entity '%s': %w", e.Name, err)
+		}
+		return nil
 	}
 
+	// keep its place in the order, only the aliases change
+	c.unindexAliases(e)
+	c.indexAliases(e)
 	return nil
 }
@@ -29,7 +29,7 @@ func (c *Container) Copy() entities.Component {
 	}
 
 	for _, child := range c.children.GetChildren() {
-		cCopy.AddChild(child)
+		cCopy.AddChild(child.Copy(cCopy))
 	}
 
 	return cCopy
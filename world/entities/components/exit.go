@@ -0,0 +1,96 @@
+package components
+
+import (
+	"fmt"
+
+	"example.com/mud/world/entities"
+)
+
+// Exit leads out of a room to another. Plain exits are always open, but an
+// exit may have a door, a lock, be hidden, or only let some through.
+type Exit struct {
+	Direction string
+	RoomId    string
+	// other names the exit goes by, e.g. "wardrobe" for "enter wardrobe"
+	Aliases []string
+
+	// doors can be opened and closed, and locked and unlocked with the key
+	Door   bool
+	Closed bool
+	Locked bool
+	// id of the entity which locks and unlocks the door
+	KeyId string
+
+	// there is no way back the same way, e.g. a slide
+	OneWay bool
+	// hidden exits can't be seen or used until something reveals them
+	Hidden bool
+
+	// every condition must hold to go through, otherwise Fail is shown
+	When []entities.Condition
+	Fail string
+}
+
+func NewExit(direction, roomId string) *Exit {
+	return &Exit{
+		Direction: direction,
+		RoomId:    roomId,
+	}
+}
+
+func (e *Exit) Copy() *Exit {
+	copied := *e
+	copied.When = append([]entities.Condition(nil), e.When...)
+	copied.Aliases = append([]string(nil), e.Aliases...)
+	return &copied
+}
+
+// Reverse makes the exit leading back the other way, in direction to roomId.
+// Its door, if any, starts out the same.
+func (e *Exit) Reverse(direction, roomId string) *Exit {
+	reverse := NewExit(direction, roomId)
+	reverse.Door = e.Door
+	reverse.Closed = e.Closed
+	reverse.Locked = e.Locked
+	reverse.KeyId = e.KeyId
+	return reverse
+}
+
+// Open reports whether the exit can be seen and walked through, conditions
+// aside.
+func (e *Exit) Open() bool {
+	return !e.Hidden && !e.Closed
+}
+
+// Allows reports whether every condition on the exit holds for ev.
+func (e *Exit) Allows(ev *entities.Event) (bool, error) {
+	return matchWhen(e.When, ev)
+}
+
+// Name is how players refer to the exit, e.g. "north door".
+func (e *Exit) Name() string {
+	if e.Door {
+		return fmt.Sprintf("%s door", e.Direction)
+	}
+	return e.Direction
+}
+
+// State is what may have changed about the exit since the world started.
+func (e *Exit) State() *entities.ExitState {
+	return &entities.ExitState{
+		Direction: e.Direction,
+		Closed:    e.Closed,
+		Locked:    e.Locked,
+		Hidden:    e.Hidden,
+	}
+}
+
+// ApplyState restores state, but only what still makes sense for the exit,
+// e.g. a door removed from its definition stays removed.
+func (e *Exit) ApplyState(state *entities.ExitState) {
+	if e.Door {
+		e.Closed = state.Closed
+		e.Locked = state.Locked
+	}
+	e.Hidden = state.Hidden
+}
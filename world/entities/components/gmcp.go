@@ -0,0 +1,32 @@
+package components
+
+import (
+	"example.com/mud/world/entities"
+)
+
+const DefaultGmcpPackage = "Char.Vitals"
+
+// Gmcp opts entity fields into out-of-band data, so clients can show them in gauges.
+type Gmcp struct {
+	Package string
+	Fields  []string
+}
+
+var _ entities.Component = &Gmcp{}
+
+func NewGmcp() *Gmcp {
+	return &Gmcp{
+		Package: DefaultGmcpPackage,
+	}
+}
+
+func (g *Gmcp) Id() entities.ComponentType {
+	return entities.ComponentGmcp
+}
+
+func (g *Gmcp) Copy() entities.Component {
+	return &Gmcp{
+		Package: g.Package,
+		Fields:  append([]string(nil), g.Fields...),
+	}
+}
@@ -2,25 +2,56 @@ package components
 
 import (
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 
 	"example.com/mud/world/entities"
 )
 
+// how a room's exits are matched up with the exits leading back
+const (
+	// exits leading back somewhere else are warned about
+	LinksCheck = "check"
+	// exits leading back are added where missing
+	LinksAuto = "auto"
+	// twisty passages, where exits needn't lead back at all
+	LinksNone = "none"
+)
+
+// DefaultMapIcon is drawn for rooms without an icon of their own.
+const DefaultMapIcon = "O"
+
 type Room struct {
 	MapIcon  string
 	MapColor string
-	Exits    map[string]string
+	// rooms are only mapped alongside others in the same zone
+	Zone string
+	// where the builder placed the room on the map, if Placed
+	X, Y   int
+	Placed bool
+	// landmarks are on every player's map, explored or not
+	Landmark bool
+	// exits by direction
+	Exits map[string]*Exit
+	// one of the Links constants
+	Links string
 
 	children entities.IChildren
+	// the entity the room belongs to, so what's in it can find it
+	owner *entities.Entity
 }
 
 var _ entities.Component = &Room{}
 var _ entities.ComponentWithChildren = &Room{}
+var _ entities.ExitHolder = &Room{}
+var _ entities.ComponentWithOwner = &Room{}
 
 func NewRoom() *Room {
 	return &Room{
-		MapIcon:  "O",
+		MapIcon:  DefaultMapIcon,
+		Exits:    map[string]*Exit{},
+		Links:    LinksCheck,
 		children: NewChildren(),
 	}
 }
@@ -30,14 +61,35 @@ func (r *Room) Id() entities.ComponentType {
 }
 
 func (r *Room) Copy() entities.Component {
+	// doors open and close, so copies get their own
+	exits := make(map[string]*Exit, len(r.Exits))
+	for direction, exit := range r.Exits {
+		exits[direction] = exit.Copy()
+	}
+
 	return &Room{
 		MapIcon:  r.MapIcon,
 		MapColor: r.MapColor,
-		Exits:    r.Exits,
+		Zone:     r.Zone,
+		X:        r.X,
+		Y:        r.Y,
+		Placed:   r.Placed,
+		Landmark: r.Landmark,
+		Exits:    exits,
+		Links:    r.Links,
 		children: r.children.Copy(),
 	}
 }
 
+func (r *Room) SetOwner(e *entities.Entity) {
+	r.owner = e
+}
+
+// Owner is the entity the room belongs to, if it was added to one.
+func (r *Room) Owner() *entities.Entity {
+	return r.owner
+}
+
 func (r *Room) AddChild(child *entities.Entity) error {
 	err := r.GetChildren().AddChild(child)
 	if err != nil {
@@ -58,20 +110,91 @@ func (r *Room) GetChildren() entities.IChildren {
 	return r.children
 }
 
+// GetNeighboringRoomId finds where the exit in direction leads, whether or
+// not it can be used.
 func (r *Room) GetNeighboringRoomId(direction string) (string, bool) {
-	roomId, ok := r.Exits[direction]
-	return roomId, ok
+	exit, ok := r.Exits[direction]
+	if !ok {
+		return "", false
+	}
+	return exit.RoomId, true
+}
+
+// GetExit finds the exit in direction, or going by that alias, unless it is
+// hidden.
+func (r *Room) GetExit(direction string) (*Exit, bool) {
+	exit, ok := r.FindExit(direction)
+	if !ok || exit.Hidden {
+		return nil, false
+	}
+	return exit, true
+}
+
+// FindExit finds the exit in direction, or going by that alias, hidden or
+// not.
+func (r *Room) FindExit(direction string) (*Exit, bool) {
+	if exit, ok := r.Exits[direction]; ok {
+		return exit, true
+	}
+	for _, candidate := range r.Exits {
+		if slices.Contains(candidate.Aliases, direction) {
+			return candidate, true
+		}
+	}
+	return nil, false
+}
+
+// VisibleExits lists the exits which aren't hidden, sorted by direction so
+// rooms always read the same.
+func (r *Room) VisibleExits() []*Exit {
+	exits := make([]*Exit, 0, len(r.Exits))
+	for _, exit := range r.Exits {
+		if !exit.Hidden {
+			exits = append(exits, exit)
+		}
+	}
+
+	sort.Slice(exits, func(i, j int) bool {
+		return exits[i].Direction < exits[j].Direction
+	})
+	return exits
 }
 
 func (r *Room) GetExitText() string {
 	var b strings.Builder
 	b.WriteString("Exits: ")
 
-	for exit := range r.Exits {
-		b.WriteString(exit)
+	for _, exit := range r.VisibleExits() {
+		b.WriteString(exit.Direction)
+		if exit.Closed {
+			b.WriteString(" (closed)")
+		}
 		b.WriteString(", ")
 	}
 
 	result := strings.TrimSuffix(b.String(), ", ")
 	return result
 }
+
+func (r *Room) ExitStates() []*entities.ExitState {
+	states := make([]*entities.ExitState, 0, len(r.Exits))
+	for _, exit := range r.Exits {
+		states = append(states, exit.State())
+	}
+
+	// saved files should be stable
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Direction < states[j].Direction
+	})
+	return states
+}
+
+// ApplyExitStates restores the state of every exit listed in states. Exits
+// since removed are skipped.
+func (r *Room) ApplyExitStates(states []*entities.ExitState) {
+	for _, state := range states {
+		if exit, ok := r.Exits[state.Direction]; ok {
+			exit.ApplyState(state)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package components
+
+import (
+	"testing"
+
+	"example.com/mud/world/entities"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoom_Owner(t *testing.T) {
+	t.Parallel()
+
+	hall := entities.NewEntity("Hall", "A draughty hall.", []string{"hall"}, nil, nil, nil).Add(NewRoom())
+	room, err := entities.RequireComponent[*Room](hall)
+	require.NoError(t, err)
+	require.Same(t, hall, room.Owner())
+
+	// copies lead back to themselves, not to the room they were copied from
+	copied := hall.Copy(nil)
+	copiedRoom, err := entities.RequireComponent[*Room](copied)
+	require.NoError(t, err)
+	require.Same(t, copied, copiedRoom.Owner())
+
+	require.Nil(t, NewRoom().Owner())
+}
@@ -12,6 +12,7 @@ const (
 	ConditionFieldEquals
 	ConditionMessageMatches
 	ConditionExpressionTrue
+	ConditionEach
 )
 
 type Condition interface {
@@ -0,0 +1,58 @@
+package conditions
+
+import (
+	"fmt"
+
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/expressions"
+)
+
+// Each goes through a collection, the items of a list or the keys of a map,
+// binding each to Name in turn. It holds when Conds hold for any of them, or
+// for all of them if All is set.
+type Each struct {
+	Name       string
+	Collection expressions.Expression
+	All        bool
+	Conds      []entities.Condition
+}
+
+var _ entities.Condition = &Each{}
+
+func (e *Each) Id() entities.ConditionType {
+	return entities.ConditionEach
+}
+
+func (e *Each) Check(ev *entities.Event) (bool, error) {
+	collection, err := e.Collection.Eval(ev)
+	if err != nil {
+		return false, fmt.Errorf("could not evaluate collection of '%s': %w", e.Name, err)
+	}
+	items, err := expressions.Items(collection)
+	if err != nil {
+		return false, fmt.Errorf("'%s' in: %w", e.Name, err)
+	}
+
+	for _, item := range items {
+		scoped := ev.With(e.Name, item)
+
+		holds := true
+		for _, c := range e.Conds {
+			ok, err := c.Check(scoped)
+			if err != nil {
+				return false, fmt.Errorf("'%s' in: %w", e.Name, err)
+			}
+			if !ok {
+				holds = false
+				break
+			}
+		}
+
+		// any needs one that holds, all needs none that don't
+		if holds != e.All {
+			return holds, nil
+		}
+	}
+
+	return e.All, nil
+}
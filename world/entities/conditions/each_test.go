@@ -0,0 +1,98 @@
+package conditions
+
+import (
+	"testing"
+
+	"example.com/mud/models"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/expressions"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEach_Check(t *testing.T) {
+	t.Parallel()
+
+	// holds for items over 2
+	overTwo := &ExpressionTrue{Expression: &expressions.ExpressionBinary{
+		Op:    expressions.OpGt,
+		Left:  &expressions.ExpressionVariable{Name: "n"},
+		Right: &expressions.ExpressionConst{V: models.VInt(2)},
+	}}
+	// holds for the key "str"
+	isStr := &ExpressionTrue{Expression: &expressions.ExpressionBinary{
+		Op:    expressions.OpEq,
+		Left:  &expressions.ExpressionVariable{Name: "n"},
+		Right: &expressions.ExpressionConst{V: models.VStr("str")},
+	}}
+
+	ints := func(il ...int) models.Value { return models.Value{K: models.KindIntList, IL: il} }
+	stats := models.VMap(map[string]models.Value{"dex": models.VInt(1), "str": models.VInt(3)})
+
+	type tc struct {
+		name       string
+		collection models.Value
+		all        bool
+		cond       entities.Condition
+		want       bool
+		wantErr    bool
+	}
+
+	cases := []tc{
+		{name: "any holds for one item", collection: ints(1, 3), cond: overTwo, want: true},
+		{name: "any fails for no items", collection: ints(1, 2), cond: overTwo, want: false},
+		{name: "all holds for every item", collection: ints(3, 4), all: true, cond: overTwo, want: true},
+		{name: "all fails for one item", collection: ints(3, 1), all: true, cond: overTwo, want: false},
+		{name: "maps go through their keys", collection: stats, cond: isStr, want: true},
+		{name: "all of a map's keys", collection: stats, all: true, cond: isStr, want: false},
+		{name: "any of nothing", collection: models.VNil(), cond: overTwo, want: false},
+		{name: "all of nothing", collection: models.VNil(), all: true, cond: overTwo, want: true},
+		{name: "not a collection", collection: models.VInt(3), cond: overTwo, wantErr: true},
+		{name: "items of the wrong kind", collection: models.Value{K: models.KindStringList, SL: []string{"a"}}, cond: overTwo, wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			each := &Each{
+				Name:       "n",
+				Collection: &expressions.ExpressionConst{V: c.collection},
+				All:        c.all,
+				Conds:      []entities.Condition{c.cond},
+			}
+
+			got, err := each.Check(&entities.Event{})
+			if c.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.want, got)
+		})
+	}
+}
+
+func TestEach_Nested(t *testing.T) {
+	t.Parallel()
+
+	// any a in [1, 2] { any b in [2, 3] { expr { a == b } } }
+	each := &Each{
+		Name:       "a",
+		Collection: &expressions.ExpressionConst{V: models.Value{K: models.KindIntList, IL: []int{1, 2}}},
+		Conds: []entities.Condition{&Each{
+			Name:       "b",
+			Collection: &expressions.ExpressionConst{V: models.Value{K: models.KindIntList, IL: []int{2, 3}}},
+			Conds: []entities.Condition{&ExpressionTrue{Expression: &expressions.ExpressionBinary{
+				Op:    expressions.OpEq,
+				Left:  &expressions.ExpressionVariable{Name: "a"},
+				Right: &expressions.ExpressionVariable{Name: "b"},
+			}}},
+		}},
+	}
+
+	ev := &entities.Event{}
+	got, err := each.Check(ev)
+	require.NoError(t, err)
+	require.True(t, got)
+	require.Empty(t, ev.Vars, "names are only bound while going through")
+}
@@ -14,6 +14,8 @@ type Entity struct {
 	mu         sync.RWMutex
 	components map[reflect.Type]Component
 
+	// id of the prototype this entity was instantiated from
+	Id          string
 	Name        string
 	Description string
 	Aliases     []string
@@ -51,6 +53,7 @@ func (e *Entity) Copy(parent ComponentWithChildren) *Entity {
 		fieldsCopy,
 		parent,
 	)
+	newEntity.Id = e.Id
 
 	for _, c := range e.components {
 		newEntity.Add(c.Copy())
@@ -117,6 +120,10 @@ func (e *Entity) Add(c Component) *Entity {
 	e.mu.Lock()
 	e.components[reflect.TypeOf(c)] = c
 	e.mu.Unlock()
+
+	if owned, ok := c.(ComponentWithOwner); ok {
+		owned.SetOwner(e)
+	}
 	return e
 }
 
@@ -3,6 +3,7 @@ package entities
 import (
 	"fmt"
 	"strconv"
+	"time"
 
 	"example.com/mud/models"
 	"example.com/mud/utils"
@@ -18,18 +19,93 @@ type Publisher interface {
 
 type Scheduler interface {
 	Add(job *scheduler.Job)
+	// Now is the time jobs are scheduled relative to, which isn't always the
+	// wall clock
+	Now() time.Time
+	// CancelOwnedBy cancels jobs belonging to owner, only those with label
+	// unless it is empty
+	CancelOwnedBy(owner any, label string) int
+}
+
+// World is what actions may ask of the world, beyond publishing and
+// scheduling.
+type World interface {
+	// Perform runs line as a command typed by actor, the way a player would
+	Perform(actor *Entity, line string) error
+	// Spawned is told about entities actions add to the world
+	Spawned(e *Entity)
+	// Destroying is told about entities actions are about to remove from
+	// the world, while they are still in it
+	Destroying(e *Entity)
+	// MoveEntity takes e through an exit of its room, the way players walk,
+	// returning why it couldn't if it didn't
+	MoveEntity(e *Entity, direction string) (string, error)
+}
+
+// events the world sends by itself, rather than players' commands. Apart from
+// tick, they are sent to the room they happen in and everything in it, with
+// the entity entering, leaving and so on as the source
+const (
+	// sent to entities with a Behavior every time they tick
+	EventTick = "tick"
+	// someone came into the room, by moving or connecting
+	EventEnter = "enter"
+	// someone went out of the room, by moving or disconnecting
+	EventLeave = "leave"
+	// a player connected, just before they enter
+	EventConnect = "connect"
+	// a player disconnected, just after they leave
+	EventDisconnect = "disconnect"
+	// something was copied into the room
+	EventSpawn = "spawn"
+	// something in the room is about to be destroyed
+	EventDestroy = "destroy"
+)
+
+// IsBuiltInEvent reports whether the world sends events of type t itself, so
+// reactions to it need no command.
+func IsBuiltInEvent(t string) bool {
+	switch t {
+	case EventTick, EventEnter, EventLeave, EventConnect, EventDisconnect, EventSpawn, EventDestroy:
+		return true
+	}
+	return false
 }
 
 type Event struct {
 	Type         string
 	Publisher    Publisher
 	Scheduler    Scheduler
+	World        World
 	EntitiesById map[string]*Entity
 	Room         *Entity
 	Source       *Entity
 	Instrument   *Entity
 	Target       *Entity
 	Message      string
+	// names bound by conditions going through a collection, see With
+	Vars map[string]models.Value
+}
+
+// With copies the event, binding name to v for expressions evaluated against
+// the copy.
+func (e *Event) With(name string, v models.Value) *Event {
+	scoped := *e
+	scoped.Vars = make(map[string]models.Value, len(e.Vars)+1)
+	for k, existing := range e.Vars {
+		scoped.Vars[k] = existing
+	}
+	scoped.Vars[name] = v
+	return &scoped
+}
+
+// Owner is the entity reacting to the event, which owns any jobs its
+// reaction schedules: the target, or the source when there's no target.
+func (e *Event) Owner() *Entity {
+	if e.Target != nil {
+		return e.Target
+	}
+	return e.Source
 }
 
 func (e *Event) GetRole(role EventRole) (*Entity, error) {
@@ -65,56 +141,9 @@ func FormatEventMessage(message string, ev *Event) (string, error) {
 
 	eventMap[EventRoleMessage.String()] = ev.Message
 
-	if ev.Source != nil {
-		role := EventRoleSource.String()
-		eventMap[role] = ev.Source.Name
-		eventMap[fmt.Sprintf("%s.description", role)] = ev.Source.Description
-
-		for f, v := range ev.Source.Fields {
-			switch v.K {
-			case models.KindBool:
-				eventMap[fmt.Sprintf("%s.%s", role, f)] = strconv.FormatBool(v.B)
-			case models.KindInt:
-				eventMap[fmt.Sprintf("%s.%s", role, f)] = strconv.FormatInt(int64(v.I), 10)
-			case models.KindString:
-				eventMap[fmt.Sprintf("%s.%s", role, f)] = v.S
-			}
-		}
-	}
-
-	if ev.Instrument != nil {
-		role := EventRoleInstrument.String()
-		eventMap[role] = ev.Instrument.Name
-		eventMap[fmt.Sprintf("%s.description", role)] = ev.Instrument.Description
-
-		for f, v := range ev.Instrument.Fields {
-			switch v.K {
-			case models.KindBool:
-				eventMap[fmt.Sprintf("%s.%s", role, f)] = strconv.FormatBool(v.B)
-			case models.KindInt:
-				eventMap[fmt.Sprintf("%s.%s", role, f)] = strconv.FormatInt(int64(v.I), 10)
-			case models.KindString:
-				eventMap[fmt.Sprintf("%s.%s", role, f)] = v.S
-			}
-		}
-	}
-
-	if ev.Target != nil {
-		role := EventRoleTarget.String()
-		eventMap[role] = ev.Target.Name
-		eventMap[fmt.Sprintf("%s.description", role)] = ev.Target.Description
-
-		for f, v := range ev.Target.Fields {
-			switch v.K {
-			case models.KindBool:
-				eventMap[fmt.Sprintf("%s.%s", role, f)] = strconv.FormatBool(v.B)
-			case models.KindInt:
-				eventMap[fmt.Sprintf("%s.%s", role, f)] = strconv.FormatInt(int64(v.I), 10)
-			case models.KindString:
-				eventMap[fmt.Sprintf("%s.%s", role, f)] = v.S
-			}
-		}
-	}
+	addEntityVars(eventMap, EventRoleSource, ev.Source)
+	addEntityVars(eventMap, EventRoleInstrument, ev.Instrument)
+	addEntityVars(eventMap, EventRoleTarget, ev.Target)
 
 	if ev.Message != "" {
 		eventMap[EventRoleMessageString] = ev.Message
@@ -127,3 +156,42 @@ func FormatEventMessage(message string, ev *Event) (string, error) {
 
 	return message, nil
 }
+
+// addEntityVars adds what can be shown of e in messages under role, e.g.
+// {source.hp}, and {source.stats.str} for the keys of maps.
+func addEntityVars(vars map[string]string, role EventRole, e *Entity) {
+	if e == nil {
+		return
+	}
+
+	name := role.String()
+	vars[name] = e.Name
+	vars[fmt.Sprintf("%s.description", name)] = e.Description
+
+	for f, v := range e.Fields {
+		if text, ok := formatValue(v); ok {
+			vars[fmt.Sprintf("%s.%s", name, f)] = text
+		}
+
+		if v.K == models.KindMap {
+			for k, mv := range v.M {
+				if text, ok := formatValue(mv); ok {
+					vars[fmt.Sprintf("%s.%s.%s", name, f, k)] = text
+				}
+			}
+		}
+	}
+}
+
+// formatValue is how v reads in a message, if it can be shown at all.
+func formatValue(v models.Value) (string, bool) {
+	switch v.K {
+	case models.KindBool:
+		return strconv.FormatBool(v.B), true
+	case models.KindInt:
+		return strconv.FormatInt(int64(v.I), 10), true
+	case models.KindString:
+		return v.S, true
+	}
+	return "", false
+}
@@ -0,0 +1,197 @@
+package expressions
+
+import (
+	"fmt"
+	"sort"
+
+	"example.com/mud/models"
+	"example.com/mud/world/entities"
+)
+
+// ExpressionMap builds a map from expressions for each key.
+type ExpressionMap struct {
+	Entries map[string]Expression
+}
+
+func (em *ExpressionMap) Eval(ev *entities.Event) (models.Value, error) {
+	m := make(map[string]models.Value, len(em.Entries))
+	for k, expr := range em.Entries {
+		v, err := expr.Eval(ev)
+		if err != nil {
+			return models.Value{}, fmt.Errorf("map key '%s': %w", k, err)
+		}
+		m[k] = v
+	}
+	return models.VMap(m), nil
+}
+
+// ExpressionIndex looks up a key of a map, e.g. source.stats["str"], or an
+// item of a list counting from 0, e.g. source.keys[0]. Keys that aren't set
+// and items past the end of the list are nil.
+type ExpressionIndex struct {
+	Sub   Expression
+	Index Expression
+}
+
+func (ei *ExpressionIndex) Eval(ev *entities.Event) (models.Value, error) {
+	v, err := ei.Sub.Eval(ev)
+	if err != nil {
+		return models.Value{}, err
+	}
+	index, err := ei.Index.Eval(ev)
+	if err != nil {
+		return models.Value{}, err
+	}
+
+	switch v.K {
+	case models.KindMap:
+		if index.K != models.KindString {
+			return models.Value{}, fmt.Errorf("map index expects a string")
+		}
+		return v.M[index.S], nil
+	case models.KindIntList, models.KindStringList, models.KindBoolList:
+		if index.K != models.KindInt {
+			return models.Value{}, fmt.Errorf("list index expects an int")
+		}
+		items, err := elements(v)
+		if err != nil {
+			return models.Value{}, err
+		}
+		if index.I < 0 || index.I >= len(items) {
+			return models.VNil(), nil
+		}
+		return items[index.I], nil
+	case models.KindNil:
+		// fields which were never set index like empty maps
+		return models.VNil(), nil
+	default:
+		return models.Value{}, fmt.Errorf("only maps and lists can be indexed")
+	}
+}
+
+// sortedKeys lists the keys of a map value in order, so iterating over it
+// always goes the same way.
+func sortedKeys(v models.Value) []string {
+	keys := make([]string, 0, len(v.M))
+	for k := range v.M {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Items lists what going through a collection visits: the items of a list,
+// or the keys of a map in order. Fields never set have nothing in them.
+func Items(collection models.Value) ([]models.Value, error) {
+	if collection.K == models.KindMap {
+		keys := sortedKeys(collection)
+		items := make([]models.Value, len(keys))
+		for i, k := range keys {
+			items[i] = models.VStr(k)
+		}
+		return items, nil
+	}
+
+	items, err := elements(collection)
+	if err != nil {
+		return nil, fmt.Errorf("only lists and maps can be gone through")
+	}
+	return items, nil
+}
+
+// contains reports whether v is an item of the list, or a key of the map,
+// collection. Fields never set contain nothing.
+func contains(collection, v models.Value) (bool, error) {
+	switch collection.K {
+	case models.KindMap:
+		if v.K != models.KindString {
+			return false, fmt.Errorf("in expects a string key for maps")
+		}
+		_, ok := collection.M[v.S]
+		return ok, nil
+	case models.KindNil:
+		return false, nil
+	}
+
+	items, err := elements(collection)
+	if err != nil {
+		return false, fmt.Errorf("in expects a list or a map")
+	}
+	for _, item := range items {
+		if equals(item, v) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// elements splits a list into its items. Fields never set are taken as empty
+// lists.
+func elements(v models.Value) ([]models.Value, error) {
+	var items []models.Value
+	switch v.K {
+	case models.KindIntList:
+		for _, i := range v.IL {
+			items = append(items, models.VInt(i))
+		}
+	case models.KindStringList:
+		for _, s := range v.SL {
+			items = append(items, models.VStr(s))
+		}
+	case models.KindBoolList:
+		for _, b := range v.BL {
+			items = append(items, models.VBool(b))
+		}
+	case models.KindNil:
+	default:
+		return nil, fmt.Errorf("expects a list")
+	}
+	return items, nil
+}
+
+// listOfKind is listOf, keeping the kind of list even when it is empty.
+func listOfKind(kind models.Kind, values []models.Value) (models.Value, error) {
+	if len(values) == 0 {
+		return models.Value{K: kind}, nil
+	}
+	return listOf(values)
+}
+
+// listOf makes a list out of values, which must all be of the same kind.
+func listOf(values []models.Value) (models.Value, error) {
+	if len(values) == 0 {
+		return models.Value{K: models.KindStringList}, nil
+	}
+
+	switch values[0].K {
+	case models.KindInt:
+		il := make([]int, len(values))
+		for i, v := range values {
+			if v.K != models.KindInt {
+				return models.Value{}, fmt.Errorf("list values must all be of the same kind")
+			}
+			il[i] = v.I
+		}
+		return models.Value{K: models.KindIntList, IL: il}, nil
+	case models.KindString:
+		sl := make([]string, len(values))
+		for i, v := range values {
+			if v.K != models.KindString {
+				return models.Value{}, fmt.Errorf("list values must all be of the same kind")
+			}
+			sl[i] = v.S
+		}
+		return models.Value{K: models.KindStringList, SL: sl}, nil
+	case models.KindBool:
+		bl := make([]bool, len(values))
+		for i, v := range values {
+			if v.K != models.KindBool {
+				return models.Value{}, fmt.Errorf("list values must all be of the same kind")
+			}
+			bl[i] = v.B
+		}
+		return models.Value{K: models.KindBoolList, BL: bl}, nil
+	default:
+		return models.Value{}, fmt.Errorf("lists can only hold ints, strings or bools")
+	}
+}
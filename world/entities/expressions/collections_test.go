@@ -0,0 +1,188 @@
+package expressions
+
+import (
+	"testing"
+
+	"example.com/mud/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpressionIndex_Eval(t *testing.T) {
+	t.Parallel()
+
+	type tc struct {
+		name    string
+		sub     Expression
+		index   models.Value
+		want    models.Value
+		wantErr string
+	}
+
+	cases := []tc{
+		{
+			name:  "first item of a list",
+			sub:   field("keys"),
+			index: models.VInt(0),
+			want:  models.VStr("brass"),
+		},
+		{
+			name:  "last item of a list",
+			sub:   field("keys"),
+			index: models.VInt(1),
+			want:  models.VStr("iron"),
+		},
+		{
+			name:  "past the end of a list",
+			sub:   field("keys"),
+			index: models.VInt(2),
+			want:  models.VNil(),
+		},
+		{
+			name:  "negative index",
+			sub:   field("keys"),
+			index: models.VInt(-1),
+			want:  models.VNil(),
+		},
+		{
+			name:  "item of an empty list",
+			sub:   field("empty"),
+			index: models.VInt(0),
+			want:  models.VNil(),
+		},
+		{
+			name:  "key of a map",
+			sub:   field("stats"),
+			index: models.VStr("str"),
+			want:  models.VInt(3),
+		},
+		{
+			name:  "key a map doesn't have",
+			sub:   field("stats"),
+			index: models.VStr("luck"),
+			want:  models.VNil(),
+		},
+		{
+			name:  "field never set",
+			sub:   field("missing"),
+			index: models.VStr("str"),
+			want:  models.VNil(),
+		},
+		{
+			name:    "list by a string",
+			sub:     field("keys"),
+			index:   models.VStr("0"),
+			wantErr: "list index expects an int",
+		},
+		{
+			name:    "map by an int",
+			sub:     field("stats"),
+			index:   models.VInt(0),
+			wantErr: "map index expects a string",
+		},
+		{
+			name:    "a string",
+			sub:     constant(models.VStr("brass")),
+			index:   models.VInt(0),
+			wantErr: "only maps and lists can be indexed",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			index := &ExpressionIndex{Sub: c.sub, Index: constant(c.index)}
+			got, err := index.Eval(ratEvent())
+			if c.wantErr != "" {
+				require.EqualError(t, err, c.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.want, got)
+		})
+	}
+}
+
+func TestExpressionBinary_In(t *testing.T) {
+	t.Parallel()
+
+	type tc struct {
+		name       string
+		item       models.Value
+		collection Expression
+		want       bool
+		wantErr    string
+	}
+
+	cases := []tc{
+		{
+			name:       "item of a list",
+			item:       models.VStr("iron"),
+			collection: field("keys"),
+			want:       true,
+		},
+		{
+			name:       "not an item of a list",
+			item:       models.VStr("gold"),
+			collection: field("keys"),
+			want:       false,
+		},
+		{
+			name:       "item of another kind",
+			item:       models.VInt(1),
+			collection: field("keys"),
+			want:       false,
+		},
+		{
+			name:       "key of a map",
+			item:       models.VStr("str"),
+			collection: field("stats"),
+			want:       true,
+		},
+		{
+			name:       "value of a map isn't a key",
+			item:       models.VStr("3"),
+			collection: field("stats"),
+			want:       false,
+		},
+		{
+			name:       "empty list",
+			item:       models.VInt(1),
+			collection: field("empty"),
+			want:       false,
+		},
+		{
+			name:       "field never set",
+			item:       models.VStr("iron"),
+			collection: field("missing"),
+			want:       false,
+		},
+		{
+			name:       "int key of a map",
+			item:       models.VInt(1),
+			collection: field("stats"),
+			wantErr:    "in expects a string key for maps",
+		},
+		{
+			name:       "in a string",
+			item:       models.VStr("b"),
+			collection: constant(models.VStr("brass")),
+			wantErr:    "in expects a list or a map",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			in := &ExpressionBinary{Op: OpIn, Left: constant(c.item), Right: c.collection}
+			got, err := in.Eval(ratEvent())
+			if c.wantErr != "" {
+				require.EqualError(t, err, c.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, models.VBool(c.want), got)
+		})
+	}
+}
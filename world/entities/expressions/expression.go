@@ -3,6 +3,7 @@ package expressions
 import (
 	"fmt"
 	"math/rand"
+	"slices"
 
 	"example.com/mud/models"
 	"example.com/mud/world/entities"
@@ -42,6 +43,21 @@ func (ef *ExpressionField) Eval(ev *entities.Event) (models.Value, error) {
 	return e.GetField(ef.F.Name), nil
 }
 
+// ExpressionVariable is a name bound by a condition going through a
+// collection, e.g. key in any key in keys(source.stats) { ... }.
+type ExpressionVariable struct{ Name string }
+
+func (ev *ExpressionVariable) Eval(event *entities.Event) (models.Value, error) {
+	if event == nil {
+		return models.Value{}, fmt.Errorf("unknown name '%s'", ev.Name)
+	}
+	v, ok := event.Vars[ev.Name]
+	if !ok {
+		return models.Value{}, fmt.Errorf("unknown name '%s'", ev.Name)
+	}
+	return v, nil
+}
+
 type ExpressionDice struct {
 	Count int
 	Sides int
@@ -110,6 +126,12 @@ func (n *ExpressionBinary) Eval(ev *entities.Event) (models.Value, error) {
 		return models.VBool(equals(l, r)), nil
 	case OpNe:
 		return models.VBool(!equals(l, r)), nil
+	case OpIn:
+		ok, err := contains(r, l)
+		if err != nil {
+			return models.Value{}, err
+		}
+		return models.VBool(ok), nil
 	case OpGt, OpGe, OpLt, OpLe:
 		if l.K != models.KindInt || r.K != models.KindInt {
 			return models.Value{}, fmt.Errorf("comparison expects ints")
@@ -180,6 +202,23 @@ func equals(a, b models.Value) bool {
 		return a.B == b.B
 	case models.KindNil:
 		return true
+	case models.KindIntList:
+		return slices.Equal(a.IL, b.IL)
+	case models.KindStringList:
+		return slices.Equal(a.SL, b.SL)
+	case models.KindBoolList:
+		return slices.Equal(a.BL, b.BL)
+	case models.KindMap:
+		if len(a.M) != len(b.M) {
+			return false
+		}
+		for k, v := range a.M {
+			other, ok := b.M[k]
+			if !ok || !equals(v, other) {
+				return false
+			}
+		}
+		return true
 	default:
 		return false
 	}
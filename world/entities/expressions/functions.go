@@ -0,0 +1,166 @@
+package expressions
+
+import (
+	"fmt"
+	"math/rand"
+
+	"example.com/mud/models"
+	"example.com/mud/world/entities"
+)
+
+type function func(args []models.Value) (models.Value, error)
+
+// functions callable from expressions, by name
+var functions = map[string]function{
+	"keys":   keysFunction,
+	"values": valuesFunction,
+	"len":    lenFunction,
+	"append": appendFunction,
+	"remove": removeFunction,
+	"random": randomFunction,
+}
+
+// IsFunction reports whether name can be called in expressions.
+func IsFunction(name string) bool {
+	_, ok := functions[name]
+	return ok
+}
+
+// ExpressionCall calls a function, e.g. keys(source.stats).
+type ExpressionCall struct {
+	Name string
+	Args []Expression
+}
+
+func (ec *ExpressionCall) Eval(ev *entities.Event) (models.Value, error) {
+	fn, ok := functions[ec.Name]
+	if !ok {
+		return models.Value{}, fmt.Errorf("unknown function '%s'", ec.Name)
+	}
+
+	args := make([]models.Value, len(ec.Args))
+	for i, arg := range ec.Args {
+		v, err := arg.Eval(ev)
+		if err != nil {
+			return models.Value{}, err
+		}
+		args[i] = v
+	}
+
+	v, err := fn(args)
+	if err != nil {
+		return models.Value{}, fmt.Errorf("%s: %w", ec.Name, err)
+	}
+	return v, nil
+}
+
+// mapArg is the only argument of a function taking a map. Fields never set
+// are taken as empty maps.
+func mapArg(args []models.Value) (models.Value, error) {
+	if len(args) != 1 {
+		return models.Value{}, fmt.Errorf("expects 1 argument, got %d", len(args))
+	}
+	switch args[0].K {
+	case models.KindMap:
+		return args[0], nil
+	case models.KindNil:
+		return models.VMap(nil), nil
+	default:
+		return models.Value{}, fmt.Errorf("expects a map")
+	}
+}
+
+// keysFunction lists the keys of a map, in order.
+func keysFunction(args []models.Value) (models.Value, error) {
+	m, err := mapArg(args)
+	if err != nil {
+		return models.Value{}, err
+	}
+	return models.Value{K: models.KindStringList, SL: sortedKeys(m)}, nil
+}
+
+// valuesFunction lists the values of a map, in the order of their keys.
+func valuesFunction(args []models.Value) (models.Value, error) {
+	m, err := mapArg(args)
+	if err != nil {
+		return models.Value{}, err
+	}
+
+	values := make([]models.Value, 0, len(m.M))
+	for _, k := range sortedKeys(m) {
+		values = append(values, m.M[k])
+	}
+	return listOf(values)
+}
+
+// lenFunction counts the items of a list, the keys of a map or the
+// characters of a string.
+func lenFunction(args []models.Value) (models.Value, error) {
+	if len(args) != 1 {
+		return models.Value{}, fmt.Errorf("expects 1 argument, got %d", len(args))
+	}
+
+	v := args[0]
+	switch v.K {
+	case models.KindString:
+		return models.VInt(len([]rune(v.S))), nil
+	case models.KindMap:
+		return models.VInt(len(v.M)), nil
+	}
+
+	items, err := elements(v)
+	if err != nil {
+		return models.Value{}, fmt.Errorf("expects a list, a map or a string")
+	}
+	return models.VInt(len(items)), nil
+}
+
+// appendFunction returns the list with a value added to the end. Appending
+// to a field never set starts a new list.
+func appendFunction(args []models.Value) (models.Value, error) {
+	if len(args) != 2 {
+		return models.Value{}, fmt.Errorf("expects 2 arguments, got %d", len(args))
+	}
+
+	items, err := elements(args[0])
+	if err != nil {
+		return models.Value{}, err
+	}
+	return listOf(append(items, args[1]))
+}
+
+// removeFunction returns the list without any items equal to a value.
+func removeFunction(args []models.Value) (models.Value, error) {
+	if len(args) != 2 {
+		return models.Value{}, fmt.Errorf("expects 2 arguments, got %d", len(args))
+	}
+
+	items, err := elements(args[0])
+	if err != nil {
+		return models.Value{}, err
+	}
+
+	kept := make([]models.Value, 0, len(items))
+	for _, item := range items {
+		if !equals(item, args[1]) {
+			kept = append(kept, item)
+		}
+	}
+	return listOfKind(args[0].K, kept)
+}
+
+// randomFunction picks an item of a list at random, or nil if it is empty.
+func randomFunction(args []models.Value) (models.Value, error) {
+	if len(args) != 1 {
+		return models.Value{}, fmt.Errorf("expects 1 argument, got %d", len(args))
+	}
+
+	items, err := elements(args[0])
+	if err != nil {
+		return models.Value{}, err
+	}
+	if len(items) == 0 {
+		return models.VNil(), nil
+	}
+	return items[rand.Intn(len(items))], nil
+}
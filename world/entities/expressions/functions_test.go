@@ -0,0 +1,222 @@
+package expressions
+
+import (
+	"testing"
+
+	"example.com/mud/models"
+	"example.com/mud/world/entities"
+	"github.com/stretchr/testify/require"
+)
+
+// a rat carrying a key ring, with nothing in its empty field
+func ratEvent() *entities.Event {
+	rat := entities.NewEntity("Rat", "A scrawny rat.", []string{"rat"}, nil, map[string]models.Value{
+		"keys":  {K: models.KindStringList, SL: []string{"brass", "iron"}},
+		"stats": models.VMap(map[string]models.Value{"dex": models.VInt(1), "str": models.VInt(3)}),
+		"empty": {K: models.KindIntList},
+	}, nil)
+	return &entities.Event{Source: rat}
+}
+
+func field(name string) Expression {
+	return &ExpressionField{F: Field{Role: entities.EventRoleSource, Name: name}}
+}
+
+func constant(v models.Value) Expression {
+	return &ExpressionConst{V: v}
+}
+
+func TestExpressionCall_Eval(t *testing.T) {
+	t.Parallel()
+
+	type tc struct {
+		name    string
+		fn      string
+		args    []Expression
+		want    models.Value
+		wantErr string
+	}
+
+	cases := []tc{
+		{
+			name: "len of a list",
+			fn:   "len",
+			args: []Expression{field("keys")},
+			want: models.VInt(2),
+		},
+		{
+			name: "len of a map",
+			fn:   "len",
+			args: []Expression{field("stats")},
+			want: models.VInt(2),
+		},
+		{
+			name: "len of a string counts letters",
+			fn:   "len",
+			args: []Expression{constant(models.VStr("café"))},
+			want: models.VInt(4),
+		},
+		{
+			name: "len of a field never set",
+			fn:   "len",
+			args: []Expression{field("missing")},
+			want: models.VInt(0),
+		},
+		{
+			name:    "len of an int",
+			fn:      "len",
+			args:    []Expression{constant(models.VInt(3))},
+			wantErr: "len: expects a list, a map or a string",
+		},
+		{
+			name:    "len without arguments",
+			fn:      "len",
+			wantErr: "len: expects 1 argument, got 0",
+		},
+		{
+			name: "append to a list",
+			fn:   "append",
+			args: []Expression{field("keys"), constant(models.VStr("gold"))},
+			want: models.Value{K: models.KindStringList, SL: []string{"brass", "iron", "gold"}},
+		},
+		{
+			name: "append to a field never set starts a list",
+			fn:   "append",
+			args: []Expression{field("missing"), constant(models.VInt(7))},
+			want: models.Value{K: models.KindIntList, IL: []int{7}},
+		},
+		{
+			name:    "append of another kind",
+			fn:      "append",
+			args:    []Expression{field("keys"), constant(models.VInt(7))},
+			wantErr: "append: list values must all be of the same kind",
+		},
+		{
+			name:    "append to a map",
+			fn:      "append",
+			args:    []Expression{field("stats"), constant(models.VStr("luck"))},
+			wantErr: "append: expects a list",
+		},
+		{
+			name:    "append of a map",
+			fn:      "append",
+			args:    []Expression{field("missing"), field("stats")},
+			wantErr: "append: lists can only hold ints, strings or bools",
+		},
+		{
+			name: "remove every match",
+			fn:   "remove",
+			args: []Expression{
+				constant(models.Value{K: models.KindIntList, IL: []int{1, 2, 1}}),
+				constant(models.VInt(1)),
+			},
+			want: models.Value{K: models.KindIntList, IL: []int{2}},
+		},
+		{
+			name: "remove the last item keeps the kind of list",
+			fn:   "remove",
+			args: []Expression{
+				constant(models.Value{K: models.KindStringList, SL: []string{"brass"}}),
+				constant(models.VStr("brass")),
+			},
+			want: models.Value{K: models.KindStringList},
+		},
+		{
+			name: "remove from an empty list",
+			fn:   "remove",
+			args: []Expression{field("empty"), constant(models.VInt(1))},
+			want: models.Value{K: models.KindIntList},
+		},
+		{
+			name: "remove from a field never set",
+			fn:   "remove",
+			args: []Expression{field("missing"), constant(models.VInt(1))},
+			want: models.VNil(),
+		},
+		{
+			name: "remove of another kind leaves the list be",
+			fn:   "remove",
+			args: []Expression{field("keys"), constant(models.VInt(1))},
+			want: models.Value{K: models.KindStringList, SL: []string{"brass", "iron"}},
+		},
+		{
+			name:    "remove from a string",
+			fn:      "remove",
+			args:    []Expression{constant(models.VStr("brass")), constant(models.VStr("b"))},
+			wantErr: "remove: expects a list",
+		},
+		{
+			name: "random of an empty list",
+			fn:   "random",
+			args: []Expression{field("empty")},
+			want: models.VNil(),
+		},
+		{
+			name: "random of a field never set",
+			fn:   "random",
+			args: []Expression{field("missing")},
+			want: models.VNil(),
+		},
+		{
+			name: "random of one item",
+			fn:   "random",
+			args: []Expression{constant(models.Value{K: models.KindBoolList, BL: []bool{true}})},
+			want: models.VBool(true),
+		},
+		{
+			name:    "random of a map",
+			fn:      "random",
+			args:    []Expression{field("stats")},
+			wantErr: "random: expects a list",
+		},
+		{
+			name: "keys of a map in order",
+			fn:   "keys",
+			args: []Expression{field("stats")},
+			want: models.Value{K: models.KindStringList, SL: []string{"dex", "str"}},
+		},
+		{
+			name: "values of a field never set",
+			fn:   "values",
+			args: []Expression{field("missing")},
+			want: models.Value{K: models.KindStringList},
+		},
+		{
+			name:    "keys of a list",
+			fn:      "keys",
+			args:    []Expression{field("keys")},
+			wantErr: "keys: expects a map",
+		},
+		{
+			name:    "unknown function",
+			fn:      "shuffle",
+			wantErr: "unknown function 'shuffle'",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			call := &ExpressionCall{Name: c.fn, Args: c.args}
+			got, err := call.Eval(ratEvent())
+			if c.wantErr != "" {
+				require.EqualError(t, err, c.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.want, got)
+		})
+	}
+}
+
+func TestExpressionCall_RandomPicksAnItem(t *testing.T) {
+	t.Parallel()
+
+	call := &ExpressionCall{Name: "random", Args: []Expression{field("keys")}}
+	for i := 0; i < 20; i++ {
+		got, err := call.Eval(ratEvent())
+		require.NoError(t, err)
+		require.Contains(t, []string{"brass", "iron"}, got.S)
+	}
+}
@@ -14,6 +14,7 @@ const (
 	OpMul
 	OpDiv
 	OpDice
+	OpIn
 )
 
 type UnaryOp uint8
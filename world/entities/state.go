@@ -0,0 +1,203 @@
+package entities
+
+import (
+	"fmt"
+	"sort"
+
+	"example.com/mud/models"
+)
+
+// EntityState is the runtime state of an entity that can be written to disk.
+// Components are not stored, they are rebuilt from the prototype with Id.
+type EntityState struct {
+	Id          string                  `json:"id"`
+	Name        string                  `json:"name"`
+	Description string                  `json:"description"`
+	Aliases     []string                `json:"aliases"`
+	Tags        []string                `json:"tags"`
+	Fields      map[string]models.Value `json:"fields"`
+	Children    []*ChildrenState        `json:"children,omitempty"`
+	Exits       []*ExitState            `json:"exits,omitempty"`
+
+	// numbers entities referred to elsewhere in a save, such as by jobs
+	Ref int `json:"ref,omitempty"`
+}
+
+// ChildrenState holds the children of one child-holding component.
+type ChildrenState struct {
+	Component string         `json:"component"`
+	Revealed  bool           `json:"revealed"`
+	Entities  []*EntityState `json:"entities"`
+}
+
+// ExitState is what can change about a room's exit while the world runs.
+type ExitState struct {
+	Direction string `json:"direction"`
+	Closed    bool   `json:"closed,omitempty"`
+	Locked    bool   `json:"locked,omitempty"`
+	Hidden    bool   `json:"hidden,omitempty"`
+}
+
+// ExitHolder is a component with exits, such as a room.
+type ExitHolder interface {
+	ExitStates() []*ExitState
+	ApplyExitStates(states []*ExitState)
+}
+
+// State captures the entity and all of its descendants.
+func (e *Entity) State() *EntityState {
+	return e.StateExcept(nil)
+}
+
+// StateExcept captures the entity and its descendants, leaving out any
+// descendant in exclude, such as connected players.
+func (e *Entity) StateExcept(exclude map[*Entity]struct{}) *EntityState {
+	return e.StateRefs(exclude, nil)
+}
+
+// StateRefs captures the entity like StateExcept, numbering every entity
+// found in refs, so they can be found again with ApplyStateRefs.
+func (e *Entity) StateRefs(exclude map[*Entity]struct{}, refs map[*Entity]int) *EntityState {
+	fields := make(map[string]models.Value, len(e.Fields))
+	for k, v := range e.Fields {
+		fields[k] = v
+	}
+
+	state := &EntityState{
+		Id:          e.Id,
+		Name:        e.Name,
+		Description: e.Description,
+		Aliases:     append([]string(nil), e.Aliases...),
+		Tags:        append([]string(nil), e.Tags...),
+		Fields:      fields,
+		Ref:         refs[e],
+	}
+
+	for _, cwc := range e.GetComponentsWithChildren() {
+		c, ok := cwc.(Component)
+		if !ok {
+			continue
+		}
+
+		children := &ChildrenState{
+			Component: c.Id().String(),
+			Revealed:  cwc.GetChildren().GetRevealed(),
+			Entities:  []*EntityState{},
+		}
+		for _, child := range cwc.GetChildren().GetChildren() {
+			if _, ok := exclude[child]; ok {
+				continue
+			}
+			children.Entities = append(children.Entities, child.StateRefs(exclude, refs))
+		}
+
+		// children are kept in maps, sort so saved files are stable
+		sort.Slice(children.Entities, func(i, j int) bool {
+			return children.Entities[i].Id+children.Entities[i].Name < children.Entities[j].Id+children.Entities[j].Name
+		})
+
+		state.Children = append(state.Children, children)
+	}
+
+	sort.Slice(state.Children, func(i, j int) bool {
+		return state.Children[i].Component < state.Children[j].Component
+	})
+
+	for _, c := range e.components {
+		if holder, ok := c.(ExitHolder); ok {
+			state.Exits = append(state.Exits, holder.ExitStates()...)
+		}
+	}
+
+	return state
+}
+
+// RestoreEntity rebuilds an entity from its saved state, copying components
+// from the prototype the state was taken from.
+func RestoreEntity(state *EntityState, prototypes map[string]*Entity, parent ComponentWithChildren) (*Entity, error) {
+	return restoreEntity(state, prototypes, parent, nil)
+}
+
+func restoreEntity(state *EntityState, prototypes map[string]*Entity, parent ComponentWithChildren, found map[int]*Entity) (*Entity, error) {
+	prototype, ok := prototypes[state.Id]
+	if !ok {
+		return nil, fmt.Errorf("restore entity '%s': unknown prototype '%s'", state.Name, state.Id)
+	}
+
+	e := prototype.Copy(parent)
+	if err := e.ApplyStateRefs(state, prototypes, found); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// ApplyState overwrites the entity in place with a saved state. The entity
+// must not be indexed in a parent yet, since aliases are replaced directly.
+func (e *Entity) ApplyState(state *EntityState, prototypes map[string]*Entity) error {
+	return e.ApplyStateRefs(state, prototypes, nil)
+}
+
+// ApplyStateRefs applies state like ApplyState, adding every entity numbered
+// by StateRefs to found as it is restored.
+func (e *Entity) ApplyStateRefs(state *EntityState, prototypes map[string]*Entity, found map[int]*Entity) error {
+	e.Name = state.Name
+	e.Description = state.Description
+	e.Aliases = append([]string(nil), state.Aliases...)
+	e.Tags = append([]string(nil), state.Tags...)
+
+	e.Fields = make(map[string]models.Value, len(state.Fields))
+	for k, v := range state.Fields {
+		e.Fields[k] = v
+	}
+
+	if state.Ref != 0 && found != nil {
+		found[state.Ref] = e
+	}
+
+	if err := e.restoreChildren(state.Children, prototypes, found); err != nil {
+		return fmt.Errorf("restore entity '%s': %w", state.Name, err)
+	}
+
+	for _, c := range e.components {
+		if holder, ok := c.(ExitHolder); ok {
+			holder.ApplyExitStates(state.Exits)
+		}
+	}
+
+	return nil
+}
+
+// restoreChildren replaces the children of every component listed in states.
+// Components missing from states keep the children they already have.
+func (e *Entity) restoreChildren(states []*ChildrenState, prototypes map[string]*Entity, found map[int]*Entity) error {
+	for _, cs := range states {
+		ct, err := ParseComponentType(cs.Component)
+		if err != nil {
+			return fmt.Errorf("restore children: %w", err)
+		}
+
+		cwc, ok := e.GetComponentWithChildren(ct)
+		if !ok {
+			// the component was removed from the definition since saving
+			continue
+		}
+
+		for _, child := range cwc.GetChildren().GetChildren() {
+			cwc.RemoveChild(child)
+		}
+		cwc.GetChildren().SetRevealed(cs.Revealed)
+
+		for _, childState := range cs.Entities {
+			child, err := restoreEntity(childState, prototypes, cwc, found)
+			if err != nil {
+				return fmt.Errorf("restore children of '%s': %w", e.Name, err)
+			}
+			if err := cwc.AddChild(child); err != nil {
+				return fmt.Errorf("restore children of '%s': %w", e.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package entities_test
+
+import (
+	"testing"
+
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/components"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntity_ApplyStateRefs(t *testing.T) {
+	t.Parallel()
+
+	prototype := func(id string, aliases []string) *entities.Entity {
+		e := entities.NewEntity(id, id+" description", aliases, nil, nil, nil)
+		e.Id = id
+		return e
+	}
+
+	cellar := prototype("Cellar", []string{"cellar"})
+	cellar.Add(components.NewRoom())
+	prototypes := map[string]*entities.Entity{
+		"Cellar": cellar,
+		"Rat":    prototype("Rat", []string{"rat"}),
+		"Dust":   prototype("Dust", nil),
+	}
+
+	// dust has no aliases, so it is never added back to the room
+	state := &entities.EntityState{
+		Id:      "Cellar",
+		Name:    "Cellar",
+		Aliases: []string{"cellar"},
+		Children: []*entities.ChildrenState{{
+			Component: entities.ComponentRoom.String(),
+			Entities: []*entities.EntityState{
+				{Id: "Dust", Name: "Dust", Ref: 3},
+				{Id: "Rat", Name: "Big Rat", Aliases: []string{"rat"}, Ref: 1},
+				{Id: "Rat", Name: "Rat", Aliases: []string{"rat"}, Ref: 2},
+			},
+		}},
+		Ref: 4,
+	}
+
+	restored := cellar.Copy(nil)
+	found := map[int]*entities.Entity{}
+	require.NoError(t, restored.ApplyStateRefs(state, prototypes, found))
+
+	require.Len(t, found, 4)
+	require.Same(t, restored, found[4])
+	require.Equal(t, "Dust", found[3].Name)
+	require.Equal(t, "Big Rat", found[1].Name)
+	require.Equal(t, "Rat", found[2].Name)
+
+	room, ok := entities.GetComponent[*components.Room](restored)
+	require.True(t, ok)
+	require.Contains(t, room.GetChildren().GetChildren(), found[1])
+	require.Contains(t, room.GetChildren().GetChildren(), found[2])
+}
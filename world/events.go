@@ -0,0 +1,68 @@
+package world
+
+import (
+	"log"
+
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/components"
+)
+
+// Spawned starts anything copied into the world acting on its own, and tells
+// the room it was copied into.
+func (w *World) Spawned(e *entities.Entity) {
+	w.startBehaviors(e)
+
+	if room := w.roomOf(e); room != nil && room != e {
+		w.roomEvent(room, e, entities.EventSpawn, "")
+	}
+}
+
+// Destroying tells the room e is in that it is about to be destroyed.
+func (w *World) Destroying(e *entities.Entity) {
+	if room := w.roomOf(e); room != nil && room != e {
+		w.roomEvent(room, e, entities.EventDestroy, "")
+	}
+}
+
+// roomEvent sends an event about source, such as it entering, to room and
+// everything in it besides source. Entering and leaving have the direction
+// moved in as their message, so others can follow.
+func (w *World) roomEvent(room, source *entities.Entity, eventType string, message string) {
+	rm, ok := entities.GetComponent[*components.Room](room)
+	if !ok {
+		return
+	}
+
+	// reactions may move things around, so only those there to begin with hear it
+	receivers := append([]*entities.Entity{room}, rm.GetChildren().GetChildren()...)
+	for _, receiver := range receivers {
+		if receiver == source {
+			continue
+		}
+
+		w.sendEvent(receiver, &entities.Event{
+			Type:    eventType,
+			Room:    room,
+			Source:  source,
+			Target:  receiver,
+			Message: message,
+		})
+	}
+}
+
+// sendEvent runs e's reactions to ev, filling in the world's part of it.
+func (w *World) sendEvent(e *entities.Entity, ev *entities.Event) {
+	eventful, ok := entities.GetComponent[*components.Eventful](e)
+	if !ok {
+		return
+	}
+
+	ev.Publisher = w
+	ev.Scheduler = w.Scheduler
+	ev.World = w
+	ev.EntitiesById = w.entityMap
+
+	if _, err := eventful.OnEvent(ev); err != nil {
+		log.Printf("%s event for '%s': %v", ev.Type, e.Name, err)
+	}
+}
@@ -0,0 +1,12 @@
+package world_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"example.com/mud/world/worldtest"
+)
+
+func TestWorld_RoomEvents(t *testing.T) {
+	worldtest.RunTranscripts(t, filepath.Join("testdata", "room_events"), "Vault")
+}
@@ -0,0 +1,123 @@
+package world
+
+import (
+	"strings"
+	"testing"
+
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/components"
+	"example.com/mud/world/entities/conditions"
+	"github.com/stretchr/testify/require"
+)
+
+func room(id string, exits ...*components.Exit) *entities.Entity {
+	r := components.NewRoom()
+	for _, exit := range exits {
+		r.Exits[exit.Direction] = exit
+	}
+
+	e := entities.NewEntity(id, "", []string{strings.ToLower(id)}, nil, nil, nil)
+	e.Id = id
+	return e.Add(r)
+}
+
+func TestWorld_FindPath(t *testing.T) {
+	t.Parallel()
+
+	closed := components.NewExit("north", "Hall")
+	closed.Door = true
+	closed.Closed = true
+
+	locked := components.NewExit("north", "Vault")
+	locked.Door = true
+	locked.Closed = true
+	locked.Locked = true
+
+	hidden := components.NewExit("east", "Shed")
+	hidden.Hidden = true
+
+	ladder := components.NewExit("up", "Loft")
+	ladder.When = []entities.Condition{&conditions.HasTag{EventRole: entities.EventRoleSource, Tag: "climber"}}
+
+	// the long way round to the kennel is through the lane and the ditch
+	entityMap := map[string]*entities.Entity{}
+	for _, r := range []*entities.Entity{
+		room("Gate", components.NewExit("east", "Yard"), components.NewExit("south", "Lane")),
+		room("Yard", components.NewExit("west", "Gate"), components.NewExit("south", "Kennel"), closed, hidden, ladder),
+		room("Kennel", components.NewExit("north", "Yard")),
+		room("Lane", components.NewExit("south", "Ditch")),
+		room("Ditch", components.NewExit("east", "Kennel")),
+		room("Hall", components.NewExit("south", "Yard"), locked),
+		room("Vault"),
+		room("Shed"),
+		room("Loft"),
+	} {
+		entityMap[r.Id] = r
+	}
+	w := &World{entityMap: entityMap}
+
+	type tc struct {
+		name   string
+		tags   []string
+		to     string
+		want   []string
+		wantOk bool
+	}
+
+	cases := []tc{
+		{
+			name:   "already there",
+			to:     "Gate",
+			want:   []string{},
+			wantOk: true,
+		},
+		{
+			name:   "the shortest way",
+			to:     "Kennel",
+			want:   []string{"east", "south"},
+			wantOk: true,
+		},
+		{
+			name:   "through a closed door",
+			to:     "Hall",
+			want:   []string{"east", "north"},
+			wantOk: true,
+		},
+		{
+			name: "not through a locked door",
+			to:   "Vault",
+		},
+		{
+			name: "not through a hidden exit",
+			to:   "Shed",
+		},
+		{
+			name: "not past an exit's conditions",
+			to:   "Loft",
+		},
+		{
+			name:   "past an exit's conditions",
+			tags:   []string{"climber"},
+			to:     "Loft",
+			want:   []string{"east", "up"},
+			wantOk: true,
+		},
+		{
+			name: "nowhere in the world",
+			to:   "Narnia",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			walker := entities.NewEntity("Alice", "", []string{"alice"}, c.tags, nil, nil)
+			path, ok := w.findPath(walker, entityMap["Gate"], func(r *entities.Entity) bool {
+				return r.Id == c.to
+			})
+			require.Equal(t, c.wantOk, ok)
+			require.Equal(t, c.want, path)
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package world
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/actions"
+	"example.com/mud/world/entities/components"
+	"example.com/mud/world/player"
+)
+
+// MissedJobs decides what happens to saved jobs that came due while the world
+// wasn't running.
+type MissedJobs string
+
+const (
+	// every missed run happens as soon as the world is restored, in order
+	MissedJobsCatchUp MissedJobs = "catchup"
+	// missed runs never happen, repeating jobs carry on from their next run
+	MissedJobsSkip MissedJobs = "skip"
+)
+
+// ParseMissedJobs reads a policy from config, catching up if it is empty.
+func ParseMissedJobs(s string) (MissedJobs, error) {
+	switch MissedJobs(s) {
+	case "", MissedJobsCatchUp:
+		return MissedJobsCatchUp, nil
+	case MissedJobsSkip:
+		return MissedJobsSkip, nil
+	}
+	return "", fmt.Errorf("unknown missed jobs policy '%s', expected '%s' or '%s'", s, MissedJobsCatchUp, MissedJobsSkip)
+}
+
+// next finds when a job saved to run at next should run, now the world is
+// back, returning false if it shouldn't run at all. Jobs repeat every, or
+// only run once if it is 0.
+func (m MissedJobs) next(next, now time.Time, every time.Duration) (time.Time, bool) {
+	if m != MissedJobsSkip || !next.Before(now) {
+		return next, true
+	}
+	if every <= 0 {
+		return time.Time{}, false
+	}
+
+	missed := now.Sub(next)/every + 1
+	return next.Add(missed * every), true
+}
+
+// JobState is a job scheduled by the definitions, saved with the world. The
+// entities in its event are numbered by their Ref in the snapshot.
+type JobState struct {
+	// the key of the action that scheduled the job
+	Action  string         `json:"action"`
+	Label   string         `json:"label,omitempty"`
+	NextRun time.Time      `json:"nextRun"`
+	Event   string         `json:"event"`
+	Message string         `json:"message,omitempty"`
+	Owner   int            `json:"owner"`
+	Roles   map[string]int `json:"roles,omitempty"`
+}
+
+// saveJobs captures every pending job scheduled by the definitions, along
+// with the numbers given to the entities they refer to. Jobs owned by
+// anything that isn't saved, like players, are left out.
+func (w *World) saveJobs(exclude map[*entities.Entity]struct{}) (map[*entities.Entity]int, []*JobState) {
+	saved := w.savedEntities(exclude)
+
+	refs := make(map[*entities.Entity]int)
+	ref := func(e *entities.Entity) int {
+		if _, ok := saved[e]; !ok {
+			return 0
+		}
+		if n, ok := refs[e]; ok {
+			return n
+		}
+		refs[e] = len(refs) + 1
+		return refs[e]
+	}
+
+	var states []*JobState
+	for _, job := range w.Scheduler.Jobs() {
+		sj, ok := job.Data.(*entities.ScheduledJob)
+		if !ok || sj.Schedule.Key() == "" {
+			continue
+		}
+
+		ev := sj.Event
+		owner := ref(ev.Owner())
+		if owner == 0 {
+			continue
+		}
+
+		state := &JobState{
+			Action:  sj.Schedule.Key(),
+			Label:   job.Label,
+			NextRun: job.NextRun,
+			Event:   ev.Type,
+			Message: ev.Message,
+			Owner:   owner,
+			Roles:   map[string]int{},
+		}
+		for _, role := range []entities.EventRole{entities.EventRoleSource, entities.EventRoleInstrument, entities.EventRoleTarget, entities.EventRoleRoom} {
+			e, err := ev.GetRole(role)
+			if err != nil {
+				continue
+			}
+			// players and what they carry are left empty
+			if n := ref(e); n != 0 {
+				state.Roles[role.String()] = n
+			}
+		}
+
+		states = append(states, state)
+	}
+
+	return refs, states
+}
+
+// every entity a snapshot holds, reachable from the top without passing
+// through exclude
+func (w *World) savedEntities(exclude map[*entities.Entity]struct{}) map[*entities.Entity]struct{} {
+	saved := make(map[*entities.Entity]struct{})
+
+	var walk func(e *entities.Entity)
+	walk = func(e *entities.Entity) {
+		if _, ok := exclude[e]; ok {
+			return
+		}
+		if _, ok := saved[e]; ok {
+			return
+		}
+		saved[e] = struct{}{}
+
+		for _, cwc := range e.GetComponentsWithChildren() {
+			for _, child := range cwc.GetChildren().GetChildren() {
+				walk(child)
+			}
+		}
+	}
+
+	for _, e := range w.entityMap {
+		walk(e)
+	}
+
+	return saved
+}
+
+// restoreJobs schedules saved jobs again, logging any that can't be.
+func (w *World) restoreJobs(states []*JobState, refs map[int]*entities.Entity) {
+	now := w.Scheduler.Now()
+	for _, state := range states {
+		if err := w.restoreJob(state, refs, now); err != nil {
+			log.Printf("restore snapshot: skipping job '%s': %v", state.Label, err)
+		}
+	}
+}
+
+func (w *World) restoreJob(state *JobState, refs map[int]*entities.Entity, now time.Time) error {
+	owner, ok := refs[state.Owner]
+	if !ok {
+		return fmt.Errorf("its owner no longer exists")
+	}
+
+	// the action may have been removed, or moved, in the definitions, and
+	// another put in its place
+	eventful, ok := entities.GetComponent[*components.Eventful](owner)
+	if !ok {
+		return fmt.Errorf("'%s' no longer reacts to anything", owner.Name)
+	}
+	schedule, ok := actions.FindSchedule(eventful.Rules, state.Action)
+	if !ok {
+		return fmt.Errorf("'%s' has nothing scheduled at %s", owner.Name, state.Action)
+	}
+	if schedule.Name() != state.Label {
+		return fmt.Errorf("'%s' schedules '%s' at %s now", owner.Name, schedule.Name(), state.Action)
+	}
+
+	next, ok := w.MissedJobs.next(state.NextRun, now, schedule.Every())
+	if !ok {
+		return nil
+	}
+
+	ev := &entities.Event{
+		Type:         state.Event,
+		Publisher:    w,
+		Scheduler:    w.Scheduler,
+		World:        w,
+		EntitiesById: w.entityMap,
+		Message:      state.Message,
+	}
+	for name, n := range state.Roles {
+		role, err := entities.ParseEventRole(name)
+		if err != nil {
+			return err
+		}
+
+		e := refs[n]
+		switch role {
+		case entities.EventRoleSource:
+			ev.Source = e
+		case entities.EventRoleInstrument:
+			ev.Instrument = e
+		case entities.EventRoleTarget:
+			ev.Target = e
+		case entities.EventRoleRoom:
+			ev.Room = e
+		}
+	}
+
+	schedule.ScheduleAt(ev, next)
+	return nil
+}
+
+func (w *World) jobsCommand(p *player.Player) (string, error) {
+	if !w.IsAdmin(p) {
+		return "Only the gods may do that.", nil
+	}
+
+	jobs := w.Scheduler.Jobs()
+	if len(jobs) == 0 {
+		return "Nothing is waiting to happen.", nil
+	}
+
+	now := w.Scheduler.Now()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d jobs waiting:", len(jobs))
+	for _, job := range jobs {
+		owner := "nobody"
+		if e, ok := job.Owner.(*entities.Entity); ok && e != nil {
+			owner = e.Name
+		}
+
+		label := job.Label
+		if label == "" {
+			label = "unlabelled"
+		}
+
+		wait := job.NextRun.Sub(now).Round(time.Second)
+		if wait < 0 {
+			wait = 0
+		}
+
+		fmt.Fprintf(&b, "\n  #%d %s: %s (in %s)", job.Id, owner, label, wait)
+	}
+
+	return b.String(), nil
+}
@@ -0,0 +1,180 @@
+package world_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"example.com/mud/world"
+	"example.com/mud/world/worldtest"
+	"github.com/stretchr/testify/require"
+)
+
+// commands are registered globally, so these tests don't run in parallel
+
+func TestWorld_RestoreJobs(t *testing.T) {
+	dir := filepath.Join("testdata", "drip")
+
+	type tc struct {
+		name   string
+		policy world.MissedJobs
+		// heard as soon as the world is back
+		caughtUp []string
+		// heard in the next two seconds
+		later []string
+	}
+
+	cases := []tc{
+		{
+			name:     "catch up",
+			policy:   world.MissedJobsCatchUp,
+			caughtUp: []string{"Drip.", "The rat squeaks.", "Drip."},
+			later:    []string{"Drip."},
+		},
+		{
+			name:     "skip",
+			policy:   world.MissedJobsSkip,
+			caughtUp: nil,
+			later:    []string{"Drip."},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "world.json")
+
+			before, err := worldtest.FromDirectory(dir, "Cell")
+			require.NoError(t, err)
+			alice, err := before.Join("Alice")
+			require.NoError(t, err)
+			for _, line := range []string{"poke drip", "poke rat"} {
+				_, err := alice.Do(line)
+				require.NoError(t, err)
+			}
+			require.NoError(t, before.SaveSnapshot(path))
+
+			snapshot, err := world.LoadSnapshot(path)
+			require.NoError(t, err)
+			require.Len(t, snapshot.Jobs, 2)
+
+			// the world comes back up after both jobs were due
+			after, err := worldtest.FromDirectory(dir, "Cell")
+			require.NoError(t, err)
+			after.MissedJobs = c.policy
+			after.Clock.Set(worldtest.Start.Add(4500 * time.Millisecond))
+
+			require.NoError(t, after.Restore(snapshot))
+			bob, err := after.Join("Bob")
+			require.NoError(t, err)
+
+			after.Advance(0)
+			require.Equal(t, c.caughtUp, bob.Messages())
+
+			after.Advance(2 * time.Second)
+			require.Equal(t, c.later, bob.Messages())
+		})
+	}
+}
+
+func TestWorld_RestoreJobsActOnWorld(t *testing.T) {
+	dir := filepath.Join("testdata", "belfry")
+
+	type tc struct {
+		name  string
+		poke  string
+		heard []string
+	}
+
+	cases := []tc{
+		{
+			name:  "performing a command",
+			poke:  "poke ringer",
+			heard: []string{"The bell clangs.", "The bell clangs."},
+		},
+		{
+			name:  "copying an entity",
+			poke:  "poke hen",
+			heard: []string{"Egg rolls out of the straw.", "The egg wobbles."},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "world.json")
+
+			before, err := worldtest.FromDirectory(dir, "Belfry")
+			require.NoError(t, err)
+			alice, err := before.Join("Alice")
+			require.NoError(t, err)
+			_, err = alice.Do(c.poke)
+			require.NoError(t, err)
+			require.NoError(t, before.SaveSnapshot(path))
+
+			snapshot, err := world.LoadSnapshot(path)
+			require.NoError(t, err)
+			require.Len(t, snapshot.Jobs, 1)
+
+			after, err := worldtest.FromDirectory(dir, "Belfry")
+			require.NoError(t, err)
+			require.NoError(t, after.Restore(snapshot))
+			bob, err := after.Join("Bob")
+			require.NoError(t, err)
+
+			after.Advance(4 * time.Second)
+			require.Equal(t, c.heard, bob.Messages())
+		})
+	}
+}
+
+func TestWorld_RestoreJobsAfterEdits(t *testing.T) {
+	type tc struct {
+		name  string
+		after string
+		heard []string
+	}
+
+	// the rat is poked in testdata/rat, then the world is loaded from after
+	cases := []tc{
+		{
+			name:  "unchanged",
+			after: "rat",
+			heard: []string{"The rat squeaks."},
+		},
+		{
+			name:  "written further down",
+			after: "rat_further_down",
+			heard: []string{"The rat squeaks."},
+		},
+		{
+			name:  "something else in its place",
+			after: "rat_replaced",
+			heard: nil,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "world.json")
+
+			before, err := worldtest.FromDirectory(filepath.Join("testdata", "rat"), "Cell")
+			require.NoError(t, err)
+			alice, err := before.Join("Alice")
+			require.NoError(t, err)
+			_, err = alice.Do("poke rat")
+			require.NoError(t, err)
+			require.NoError(t, before.SaveSnapshot(path))
+
+			snapshot, err := world.LoadSnapshot(path)
+			require.NoError(t, err)
+
+			after, err := worldtest.FromDirectory(filepath.Join("testdata", c.after), "Cell")
+			require.NoError(t, err)
+			require.NoError(t, after.Restore(snapshot))
+			bob, err := after.Join("Bob")
+			require.NoError(t, err)
+
+			after.Advance(3 * time.Second)
+			require.Equal(t, c.heard, bob.Messages())
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package world
+
+// The world is changed by one goroutine only, its loop. Player commands,
+// scheduled jobs, reloads and snapshots are all queued up and run on it in
+// turn, so nothing in the world needs a lock of its own. Connections only
+// read and write to their players.
+
+// run is the world's loop, running everything queued with Do, one at a time.
+func (w *World) run() {
+	for fn := range w.queue {
+		fn()
+	}
+}
+
+// Do runs fn on the world's loop, and waits for it to finish. fn may change
+// the world freely, but must not call Do itself, nor anything else waiting on
+// the loop.
+func (w *World) Do(fn func()) {
+	done := make(chan struct{})
+	w.queue <- func() {
+		defer close(done)
+		fn()
+	}
+	<-done
+}
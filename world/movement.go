@@ -0,0 +1,150 @@
+package world
+
+import (
+	"fmt"
+
+	"example.com/mud/parser/commands"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/components"
+	"example.com/mud/world/player"
+)
+
+// MoveEntity takes e out of its room through the exit in direction, the way
+// players walk, returning why it couldn't if it didn't. Players moved this way
+// are shown where they end up. It must be called from the world's loop.
+func (w *World) MoveEntity(e *entities.Entity, direction string) (string, error) {
+	// e.g. two guards sending each other back and forth
+	if w.performing >= maxPerformDepth {
+		return "", fmt.Errorf("move '%s' %s: too many moves in a row", e.Name, direction)
+	}
+	w.performing++
+	defer func() { w.performing-- }()
+
+	direction = commands.CanonicalDirection(direction)
+
+	if p := w.playerFor(e); p != nil {
+		from := p.CurrentRoom
+		reply, err := w.MovePlayer(p, direction)
+		if err != nil {
+			return "", err
+		}
+		if p.CurrentRoom == from {
+			return reply, nil
+		}
+
+		w.PublishTo(p.CurrentRoom, p.Entity, reply)
+		return "", nil
+	}
+
+	room := w.roomOf(e)
+	if room == nil || room == e {
+		return "", fmt.Errorf("move '%s' %s: not in a room", e.Name, direction)
+	}
+
+	newRoom, refusal, err := w.throughExit(e, room, direction)
+	if err != nil || newRoom == nil {
+		return refusal, err
+	}
+
+	w.relocate(e, room, newRoom, direction, nil)
+	return "", nil
+}
+
+// throughExit finds the room e would reach leaving room by direction, or why
+// it can't.
+func (w *World) throughExit(e, room *entities.Entity, direction string) (*entities.Entity, string, error) {
+	rm, err := entities.RequireComponent[*components.Room](room)
+	if err != nil {
+		return nil, "", fmt.Errorf("move for '%s': %w", e.Name, err)
+	}
+
+	exit, ok := rm.GetExit(direction)
+	if !ok {
+		return nil, "You can't go there.", nil
+	}
+	newRoom, ok := w.entityMap[exit.RoomId]
+	if !ok {
+		return nil, "You can't go there.", nil
+	}
+
+	refusal, err := w.checkExit(e, room, exit, newRoom)
+	if err != nil || refusal != "" {
+		return nil, refusal, err
+	}
+
+	return newRoom, "", nil
+}
+
+// checkExit explains why e can't go through exit from room to newRoom, or
+// returns nothing if it can.
+func (w *World) checkExit(e, room *entities.Entity, exit *components.Exit, newRoom *entities.Entity) (string, error) {
+	if exit.Closed {
+		return fmt.Sprintf("The %s is closed.", exit.Name()), nil
+	}
+
+	ev := w.moveEvent(e, room, newRoom)
+	allowed, err := exit.Allows(ev)
+	if err != nil {
+		return "", fmt.Errorf("exit '%s' for '%s': %w", exit.Direction, e.Name, err)
+	}
+	if allowed {
+		return "", nil
+	}
+
+	if exit.Fail == "" {
+		return "You can't go there.", nil
+	}
+
+	message, err := entities.FormatEventMessage(exit.Fail, ev)
+	if err != nil {
+		return "", fmt.Errorf("exit '%s' for '%s': %w", exit.Direction, e.Name, err)
+	}
+	return message, nil
+}
+
+// moveEvent is what exit conditions are checked against when e goes from
+// room to newRoom.
+func (w *World) moveEvent(e, room, newRoom *entities.Entity) *entities.Event {
+	return &entities.Event{
+		Type:         "move",
+		Publisher:    w,
+		Scheduler:    w.Scheduler,
+		World:        w,
+		EntitiesById: w.entityMap,
+		Room:         room,
+		Source:       e,
+		Target:       newRoom,
+	}
+}
+
+// relocate moves e from one room to another by direction, telling both.
+// arrived runs once e is in the new room, before anyone there hears of it.
+// Reactions only run once e has fully moved, so anything following it
+// arrives after it.
+func (w *World) relocate(e, from, to *entities.Entity, direction string, arrived func()) {
+	w.Publish(from, fmt.Sprintf("%s leaves the room.", e.Name), []*entities.Entity{e})
+
+	if room, ok := entities.GetComponent[*components.Room](from); ok {
+		room.RemoveChild(e)
+	}
+	if room, ok := entities.GetComponent[*components.Room](to); ok {
+		room.AddChild(e)
+	}
+	if arrived != nil {
+		arrived()
+	}
+
+	w.Publish(to, fmt.Sprintf("%s enters the room.", e.Name), []*entities.Entity{e})
+
+	w.roomEvent(from, e, entities.EventLeave, direction)
+	w.roomEvent(to, e, entities.EventEnter, direction)
+}
+
+// namedExit finds the exit in the player's room named exactly line.
+func (w *World) namedExit(p *player.Player, line string) (*components.Exit, bool) {
+	rm, ok := entities.GetComponent[*components.Room](p.CurrentRoom)
+	if !ok {
+		return nil, false
+	}
+	return rm.GetExit(commands.NormalizeExitName(line))
+}
@@ -0,0 +1,28 @@
+package world_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"example.com/mud/parser/commands"
+	"example.com/mud/world/worldtest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorld_MoveEntities(t *testing.T) {
+	worldtest.RunTranscripts(t, filepath.Join("testdata", "go"), "Yard")
+}
+
+func TestWorld_Directions(t *testing.T) {
+	dir := filepath.Join("testdata", "directions")
+
+	w, err := worldtest.FromDirectory(dir, "Courtyard")
+	require.NoError(t, err)
+
+	// opposites only need giving one way
+	opposite, ok := commands.OppositeDirection("sunwise")
+	require.True(t, ok)
+	require.Equal(t, "widdershins", opposite)
+
+	worldtest.RunTranscript(t, w, filepath.Join(dir, "directions.txt"))
+}
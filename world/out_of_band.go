@@ -0,0 +1,32 @@
+package world
+
+import (
+	"log"
+
+	"example.com/mud/world/scheduler"
+)
+
+// SyncOutOfBand pushes any changed out-of-band data to every connected player.
+// Anything that might mutate the world should call this once it is done, from
+// the world's loop.
+func (w *World) SyncOutOfBand() {
+	for _, p := range w.players {
+		if err := p.SyncOutOfBand(); err != nil {
+			log.Printf("out of band: %v", err)
+		}
+	}
+}
+
+// runJob runs scheduled jobs on the world's loop, like commands, and syncs
+// out-of-band data after each one, since scheduled reactions change the world
+// without any player command.
+func (w *World) runJob(job *scheduler.Job) {
+	w.Do(func() {
+		if w.closed {
+			return
+		}
+
+		job.RunFunc()
+		w.SyncOutOfBand()
+	})
+}
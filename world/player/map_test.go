@@ -0,0 +1,66 @@
+package player_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"example.com/mud/world/player"
+	"example.com/mud/world/worldtest"
+	"github.com/stretchr/testify/require"
+)
+
+// commands are registered globally, so these tests don't run in parallel
+
+func TestPlayer_Map(t *testing.T) {
+	dir := filepath.Join("testdata", "map")
+
+	type tc struct {
+		transcript   string
+		startingRoom string
+	}
+
+	cases := []tc{
+		{transcript: "town.txt", startingRoom: "Square"},
+		{transcript: "tower.txt", startingRoom: "Landing"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.transcript, func(t *testing.T) {
+			w, err := worldtest.FromDirectory(dir, c.startingRoom)
+			require.NoError(t, err)
+			// every room is drawn, explored or not
+			w.RevealMap = true
+
+			worldtest.RunTranscript(t, w, filepath.Join(dir, c.transcript))
+		})
+	}
+}
+
+func TestPlayer_FogOfWar(t *testing.T) {
+	dir := filepath.Join("testdata", "fog")
+
+	w, err := worldtest.FromDirectory(dir, "Square")
+	require.NoError(t, err)
+	worldtest.RunTranscript(t, w, filepath.Join(dir, "fog.txt"))
+
+	// explored rooms are saved with the player
+	alice, ok := w.Player("Alice")
+	require.True(t, ok)
+
+	var state *player.State
+	w.Do(func() {
+		state = alice.State()
+	})
+	require.Equal(t, []string{"Market", "Square"}, state.Explored)
+
+	w.Leave(alice)
+	again, err := w.AddPlayer("Alice", make(chan string, 16), nil, state)
+	require.NoError(t, err)
+
+	var explored bool
+	w.Do(func() {
+		market, _ := w.Entity("Market")
+		explored = again.Explored(market)
+	})
+	require.True(t, explored)
+}
@@ -0,0 +1,155 @@
+package player
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"example.com/mud/models"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/components"
+)
+
+const (
+	PackageRoomInfo  = "Room.Info"
+	PackageCharItems = "Char.Items.List"
+)
+
+type roomInfo struct {
+	Id    string            `json:"id"`
+	Name  string            `json:"name"`
+	Exits map[string]string `json:"exits"`
+	Icon  string            `json:"icon"`
+	Color string            `json:"color"`
+	// clients call zones areas
+	Zone string `json:"area,omitempty"`
+}
+
+type itemInfo struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type itemList struct {
+	Location string     `json:"location"`
+	Items    []itemInfo `json:"items"`
+}
+
+// SyncOutOfBand sends every out-of-band package whose contents changed since
+// it was last delivered to this player. Packages that don't fit in the
+// channel are kept until FlushOutOfBand.
+func (p *Player) SyncOutOfBand() error {
+	if p.data == nil {
+		return nil
+	}
+
+	packages, err := p.outOfBandPackages()
+	if err != nil {
+		return fmt.Errorf("sync out of band for player '%s': %w", p.Name, err)
+	}
+
+	names := make([]string, 0, len(packages))
+	for name := range packages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, name := range names {
+		payload, err := json.Marshal(packages[name])
+		if err != nil {
+			return fmt.Errorf("sync out of band for player '%s' package '%s': %w", p.Name, name, err)
+		}
+
+		if p.sentData[name] == string(payload) {
+			// changed back before the last change could be delivered
+			delete(p.pendingData, name)
+			continue
+		}
+
+		p.pendingData[name] = models.OutOfBand{Package: name, Data: payload}
+	}
+
+	p.flushOutOfBand()
+	return nil
+}
+
+// FlushOutOfBand sends the packages SyncOutOfBand couldn't fit in the
+// channel. The connection calls it each time it takes a package off the
+// channel, as there is room again.
+func (p *Player) FlushOutOfBand() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.flushOutOfBand()
+}
+
+func (p *Player) flushOutOfBand() {
+	names := make([]string, 0, len(p.pendingData))
+	for name := range p.pendingData {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		msg := p.pendingData[name]
+		select {
+		case p.data <- msg:
+			p.sentData[name] = string(msg.Data)
+			delete(p.pendingData, name)
+		default:
+			// the receiver is slow, the rest wait until it takes some
+			return
+		}
+	}
+}
+
+func (p *Player) outOfBandPackages() (map[string]any, error) {
+	packages := make(map[string]any, 3)
+
+	room, err := entities.RequireComponent[*components.Room](p.CurrentRoom)
+	if err != nil {
+		return nil, err
+	}
+
+	// hidden exits aren't given away
+	exits := make(map[string]string, len(room.Exits))
+	for _, exit := range room.VisibleExits() {
+		exits[exit.Direction] = exit.RoomId
+	}
+
+	packages[PackageRoomInfo] = roomInfo{
+		Id:    p.CurrentRoom.Id,
+		Name:  p.CurrentRoom.Name,
+		Exits: exits,
+		Icon:  room.MapIcon,
+		Color: room.MapColor,
+		Zone:  room.Zone,
+	}
+
+	if inventory, ok := entities.GetComponent[*components.Inventory](p.Entity); ok {
+		items := itemList{
+			Location: "inv",
+			Items:    []itemInfo{},
+		}
+		for _, child := range inventory.GetChildren().GetChildren() {
+			items.Items = append(items.Items, itemInfo{Id: child.Id, Name: child.Name})
+		}
+		sort.Slice(items.Items, func(i, j int) bool {
+			return items.Items[i].Name < items.Items[j].Name
+		})
+		packages[PackageCharItems] = items
+	}
+
+	if gmcp, ok := entities.GetComponent[*components.Gmcp](p.Entity); ok {
+		fields := make(map[string]any, len(gmcp.Fields))
+		for _, f := range gmcp.Fields {
+			fields[f] = p.Entity.GetField(f).Any()
+		}
+		packages[gmcp.Package] = fields
+	}
+
+	return packages, nil
+}
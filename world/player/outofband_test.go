@@ -0,0 +1,63 @@
+package player
+
+import (
+	"testing"
+
+	"example.com/mud/models"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/components"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlayer_SyncOutOfBand(t *testing.T) {
+	t.Parallel()
+
+	hall := entities.NewEntity("Hall", "A draughty hall.", []string{"hall"}, nil, nil, nil).Add(components.NewRoom())
+	hall.Id = "Hall"
+	alice := entities.NewEntity("Alice", "", []string{"alice"}, nil, nil, nil).Add(components.NewInventory())
+
+	// room for one package at a time
+	data := make(chan models.OutOfBand, 1)
+	p := &Player{
+		Name:        "Alice",
+		Entity:      alice,
+		CurrentRoom: hall,
+		data:        data,
+		sentData:    map[string]string{},
+		pendingData: map[string]models.OutOfBand{},
+	}
+	filler := models.OutOfBand{Package: "Core.Ping"}
+
+	// the room doesn't fit, and goes once the items are taken
+	require.NoError(t, p.SyncOutOfBand())
+	require.Equal(t, PackageCharItems, (<-data).Package)
+	require.Empty(t, data)
+	p.FlushOutOfBand()
+	require.Equal(t, PackageRoomInfo, (<-data).Package)
+
+	// nothing changed, so nothing is sent
+	require.NoError(t, p.SyncOutOfBand())
+	require.Empty(t, data)
+
+	// only the latest of several changes waits
+	data <- filler
+	hall.Name = "Great Hall"
+	require.NoError(t, p.SyncOutOfBand())
+	hall.Name = "Grand Hall"
+	require.NoError(t, p.SyncOutOfBand())
+	<-data
+	p.FlushOutOfBand()
+	require.Contains(t, string((<-data).Data), `"Grand Hall"`)
+	p.FlushOutOfBand()
+	require.Empty(t, data)
+
+	// changes undone before they could go aren't sent at all
+	data <- filler
+	hall.Name = "Hall"
+	require.NoError(t, p.SyncOutOfBand())
+	hall.Name = "Grand Hall"
+	require.NoError(t, p.SyncOutOfBand())
+	<-data
+	p.FlushOutOfBand()
+	require.Empty(t, data)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -12,7 +13,6 @@ import (
 	"example.com/mud/utils"
 	"example.com/mud/world/entities"
 	"example.com/mud/world/entities/components"
-	"example.com/mud/world/scheduler"
 )
 
 var safeNameRegex = regexp.MustCompile(`[^a-zA-Z]+`)
@@ -27,20 +27,36 @@ type Player struct {
 	nextActionAt  time.Time
 	trackingAlias string
 	world         World
+	// ids of the rooms the player has been to, drawn on their map
+	explored map[string]bool
+
+	// out-of-band data channel, the last payload delivered per package, and
+	// the latest payload per package still waiting for room in the channel
+	data        chan models.OutOfBand
+	sentData    map[string]string
+	pendingData map[string]models.OutOfBand
+
+	// closed once the player has been disconnected from the world
+	done     chan struct{}
+	doneOnce sync.Once
 }
 
 type World interface {
 	EntitiesById() map[string]*entities.Entity
 	GetEntityById(id string) (*entities.Entity, bool)
 	MovePlayer(p *Player, direction string) (string, error)
+	// whether maps show every room, rather than only those explored
+	MapRevealed() bool
 
 	Publish(room *entities.Entity, text string, exclude []*entities.Entity)
 	PublishTo(room *entities.Entity, recipient *entities.Entity, text string)
 
-	GetScheduler() *scheduler.Scheduler
+	GetScheduler() entities.Scheduler
+
+	entities.World
 }
 
-func NewPlayer(name string, world World, currentRoom *entities.Entity) (*Player, error) {
+func NewPlayer(name string, world World, currentRoom *entities.Entity, data chan models.OutOfBand) (*Player, error) {
 	playerTemplate, ok := world.GetEntityById("Player")
 	if !ok {
 		return nil, fmt.Errorf("entity with ID 'Player' does not exist in world")
@@ -56,9 +72,42 @@ func NewPlayer(name string, world World, currentRoom *entities.Entity) (*Player,
 		Entity:      playerEntity,
 		CurrentRoom: currentRoom,
 		world:       world,
+		explored:    map[string]bool{currentRoom.Id: true},
+		data:        data,
+		sentData:    map[string]string{},
+		pendingData: map[string]models.OutOfBand{},
+		done:        make(chan struct{}),
 	}, nil
 }
 
+// NewActor wraps an entity already in the world, such as an NPC, so it can
+// run commands like a player. Actors have no connection, so nothing is sent
+// to them.
+func NewActor(entity *entities.Entity, world World, currentRoom *entities.Entity) *Player {
+	return &Player{
+		Name:        entity.Name,
+		Entity:      entity,
+		CurrentRoom: currentRoom,
+		world:       world,
+		explored:    map[string]bool{},
+		sentData:    map[string]string{},
+		pendingData: map[string]models.OutOfBand{},
+		done:        make(chan struct{}),
+	}
+}
+
+// Done is closed when the player is disconnected, e.g. when the server shuts down.
+func (p *Player) Done() <-chan struct{} {
+	return p.done
+}
+
+// Disconnect marks the player as disconnected, it is safe to call more than once.
+func (p *Player) Disconnect() {
+	p.doneOnce.Do(func() {
+		close(p.done)
+	})
+}
+
 func (p *Player) OpeningMessage() (string, error) {
 	message, err := p.GetRoomDescription()
 	if err != nil {
@@ -200,6 +249,7 @@ func (p *Player) ActMessage(action, message, noMatchMessage string) (string, err
 		Type:         action,
 		Publisher:    p.world,
 		Scheduler:    p.world.GetScheduler(),
+		World:        p.world,
 		EntitiesById: p.world.EntitiesById(),
 		Room:         p.CurrentRoom,
 		Source:       p.Entity,
@@ -241,6 +291,7 @@ func (p *Player) actUponEntity(action string, target *entities.Entity, noMatchMe
 		Type:         action,
 		Publisher:    p.world,
 		Scheduler:    p.world.GetScheduler(),
+		World:        p.world,
 		EntitiesById: p.world.EntitiesById(),
 		Room:         p.CurrentRoom,
 		Source:       p.Entity,
@@ -282,6 +333,7 @@ func (p *Player) actUponMessageEntity(action string, target *entities.Entity, me
 		Type:         action,
 		Publisher:    p.world,
 		Scheduler:    p.world.GetScheduler(),
+		World:        p.world,
 		EntitiesById: p.world.EntitiesById(),
 		Room:         p.CurrentRoom,
 		Source:       p.Entity,
@@ -354,6 +406,7 @@ func (p *Player) actUponWithEntities(action string, target, instrument *entities
 		Type:         action,
 		Publisher:    p.world,
 		Scheduler:    p.world.GetScheduler(),
+		World:        p.world,
 		EntitiesById: p.world.EntitiesById(),
 		Room:         p.CurrentRoom,
 		Source:       p.Entity,
@@ -417,3 +470,57 @@ func (p *Player) getEntitiesByAlias(alias string) ([]entities.AmbiguityOption, e
 
 	return eMatches, nil
 }
+
+// State is what is kept of a player between sessions.
+type State struct {
+	Room   string                `json:"room"`
+	Entity *entities.EntityState `json:"entity"`
+	// ids of the rooms the player has explored
+	Explored []string `json:"explored,omitempty"`
+}
+
+func (p *Player) State() *State {
+	explored := make([]string, 0, len(p.explored))
+	for id := range p.explored {
+		explored = append(explored, id)
+	}
+	sort.Strings(explored)
+
+	return &State{
+		Room:     p.CurrentRoom.Id,
+		Entity:   p.Entity.State(),
+		Explored: explored,
+	}
+}
+
+// Explore marks room as somewhere the player has been.
+func (p *Player) Explore(room *entities.Entity) {
+	p.explored[room.Id] = true
+}
+
+// Explored reports whether the player has been to room.
+func (p *Player) Explored(room *entities.Entity) bool {
+	return p.explored[room.Id]
+}
+
+// RestoreExplored marks every room in ids as explored, e.g. from a saved
+// State. Rooms since removed are dropped.
+func (p *Player) RestoreExplored(ids []string) {
+	for _, id := range ids {
+		if room, ok := p.world.GetEntityById(id); ok {
+			p.Explore(room)
+		}
+	}
+}
+
+// Restore replaces the player's entity with one rebuilt from a saved state.
+// The player must not have been placed in a room yet.
+func (p *Player) Restore(state *entities.EntityState) error {
+	entity, err := entities.RestoreEntity(state, p.world.EntitiesById(), nil)
+	if err != nil {
+		return fmt.Errorf("restore player '%s': %w", p.Name, err)
+	}
+
+	p.Entity = entity
+	return nil
+}
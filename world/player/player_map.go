@@ -5,12 +5,15 @@ import (
 	"strings"
 
 	"example.com/mud/models"
+	"example.com/mud/parser/commands"
 	"example.com/mud/world/entities"
 	"example.com/mud/world/entities/components"
 )
 
 func (p *Player) Map() (string, error) {
-	coordByRoom, err := assignCoordinates(p.CurrentRoom, p.world, 5)
+	p.Explore(p.CurrentRoom)
+
+	layout, err := assignCoordinates(p.CurrentRoom, p.world, 5)
 	if err != nil {
 		return "", fmt.Errorf("map: assign coordinates: %w", err)
 	}
@@ -20,39 +23,83 @@ func (p *Player) Map() (string, error) {
 		return "", fmt.Errorf("cannot map non-room area: %w", err)
 	}
 
-	ascii, err := p.renderMap(coordByRoom, currentRoom, p.world)
+	ascii, err := p.renderMap(layout, currentRoom, p.world)
 	if err != nil {
 		return "", fmt.Errorf("map: render map: %w", err)
 	}
+	if ascii == "" {
+		return "", nil
+	}
 
-	return ascii, nil
+	return models.Preformatted + ascii, nil
 }
 
 type coord struct{ X, Y int }
 
-var cardinalDelta = map[string]coord{
-	"north": {0, -1},
-	"south": {0, 1},
-	"east":  {1, 0},
-	"west":  {-1, 0},
+// mapLayout is where each room is drawn, and the exits that couldn't be drawn
+// leading to their room because something else is in the way.
+type mapLayout struct {
+	coordByRoom  map[*components.Room]coord
+	entityByRoom map[*components.Room]*entities.Entity
+	twisted      map[*components.Exit]bool
 }
 
-// fixed-order so random ranging over map doesn't influence mapping
-var dirOrder = []string{"north", "east", "south", "west"}
+// symbols drawn on the map, in the order the legend lists them
+var mapLegend = []struct{ symbol, meaning string }{
+	{"@", "you"},
+	{"!", "tracked"},
+	{"^", "up"},
+	{"v", "down"},
+	{"%", "up and down"},
+	{"+", "closed door"},
+	{"~", "twisting passage"},
+	{"?", "unexplored"},
+}
 
-func assignCoordinates(start *entities.Entity, world World, maxDepth int) (map[*components.Room]coord, error) {
-	coordByRoom := make(map[*components.Room]coord)
+// mapDelta is the step exit takes across the map, if it is drawn at all.
+// Exits up, down or by name, e.g. "wardrobe", aren't.
+func mapDelta(exit *components.Exit) (coord, bool) {
+	d, ok := commands.Directions[exit.Direction]
+	if !ok || !d.Mapped {
+		return coord{}, false
+	}
+	return coord{d.X, d.Y}, true
+}
+
+// assignCoordinates walks out from start, placing each room a step from the
+// room it was reached from, or where the builder placed it. Rooms in other
+// zones are left off. Rooms that would be drawn over another are left off
+// too, and the exit leading to them is drawn as twisting away.
+func assignCoordinates(start *entities.Entity, world World, maxDepth int) (*mapLayout, error) {
+	layout := &mapLayout{
+		coordByRoom:  make(map[*components.Room]coord),
+		entityByRoom: make(map[*components.Room]*entities.Entity),
+		twisted:      make(map[*components.Exit]bool),
+	}
 	roomAtCoord := make(map[coord]*components.Room)
-	seen := make(map[*components.Room]bool)
+
+	startRoom, err := entities.RequireComponent[*components.Room](start)
+	if err != nil {
+		return nil, fmt.Errorf("cannot map non-room: %w", err)
+	}
+
+	// builder coordinates only mean something next to other placed rooms
+	placed := startRoom.Placed
+	origin := coord{}
+	if placed {
+		origin = coord{startRoom.X, startRoom.Y}
+	}
 
 	type item struct {
 		e     *entities.Entity
-		x, y  int
+		c     coord
 		depth int
+		// the exit taken to get here
+		via *components.Exit
 	}
 
 	// queue with index-based pop (no O(n) slice shifting)
-	queue := []item{{e: start, x: 0, y: 0, depth: 0}}
+	queue := []item{{e: start, c: origin}}
 	head := 0
 
 	for head < len(queue) {
@@ -67,31 +114,35 @@ func assignCoordinates(start *entities.Entity, world World, maxDepth int) (map[*
 		if err != nil {
 			return nil, fmt.Errorf("cannot map non-room: %w", err)
 		}
-		if seen[r] {
+		if _, seen := layout.coordByRoom[r]; seen {
 			continue
 		}
 
-		c := coord{it.x, it.y}
+		c := it.c
+		if placed && r.Placed {
+			c = coord{r.X, r.Y}
+		}
 		if existing, ok := roomAtCoord[c]; ok && existing != r {
-			return nil, fmt.Errorf("mapping non-euclidean space at %v", c)
+			if it.via != nil {
+				layout.twisted[it.via] = true
+			}
+			continue
 		}
 
-		seen[r] = true
-		coordByRoom[r] = c
+		layout.coordByRoom[r] = c
+		layout.entityByRoom[r] = it.e
 		roomAtCoord[c] = r
 
-		// Expand neighbors in deterministic NESW order
-		exits := r.Exits
-		for _, dir := range dirOrder {
-			roomID, ok := exits[dir]
+		// exits are sorted so random ranging over map doesn't influence mapping
+		for _, exit := range r.VisibleExits() {
+			delta, ok := mapDelta(exit)
 			if !ok {
 				continue
 			}
-			delta := cardinalDelta[dir]
 
-			nextEntity, ok := world.GetEntityById(roomID)
+			nextEntity, ok := world.GetEntityById(exit.RoomId)
 			if !ok {
-				return nil, fmt.Errorf("entity with id %q does not exist", roomID)
+				return nil, fmt.Errorf("entity with id %q does not exist", exit.RoomId)
 			}
 
 			// Optional early type check (helps avoid enqueuing non-rooms)
@@ -99,29 +150,93 @@ func assignCoordinates(start *entities.Entity, world World, maxDepth int) (map[*
 			if err != nil {
 				return nil, fmt.Errorf("cannot map non-room: %w", err)
 			}
-			if seen[nr] {
+			if nr.Zone != startRoom.Zone {
+				continue
+			}
+			if _, seen := layout.coordByRoom[nr]; seen {
 				continue
 			}
 
 			queue = append(queue, item{
 				e:     nextEntity,
-				x:     it.x + delta.X,
-				y:     it.y + delta.Y,
+				c:     coord{c.X + delta.X, c.Y + delta.Y},
 				depth: it.depth + 1,
+				via:   exit,
 			})
 		}
 	}
 
-	return coordByRoom, nil
+	return layout, nil
+}
+
+// mapView is what the player knows of the rooms laid out on their map.
+type mapView struct {
+	// rooms drawn with their icon, and those with their exits drawn too
+	known, explored map[*components.Room]bool
+	// rooms seen through an explored room's exits, drawn as "?"
+	glimpsed map[*components.Room]bool
+}
+
+// viewMap works out what the player knows of layout. Players know the rooms
+// they have been to and their exits, and landmarks, unless the whole map is
+// revealed.
+func (p *Player) viewMap(layout *mapLayout, world World) mapView {
+	view := mapView{
+		known:    map[*components.Room]bool{},
+		explored: map[*components.Room]bool{},
+		glimpsed: map[*components.Room]bool{},
+	}
+
+	revealed := world.MapRevealed()
+	for r, e := range layout.entityByRoom {
+		if revealed || p.Explored(e) {
+			view.explored[r] = true
+			view.known[r] = true
+		} else if r.Landmark {
+			view.known[r] = true
+		}
+	}
+
+	for r := range view.explored {
+		for _, exit := range r.VisibleExits() {
+			next, ok := world.GetEntityById(exit.RoomId)
+			if !ok {
+				continue
+			}
+			nr, ok := entities.GetComponent[*components.Room](next)
+			if !ok || view.known[nr] {
+				continue
+			}
+			if _, ok := layout.coordByRoom[nr]; ok {
+				view.glimpsed[nr] = true
+			}
+		}
+	}
+
+	return view
 }
 
-func (p *Player) renderMap(coordByRoom map[*components.Room]coord, currentRoom *components.Room, world World) (string, error) {
+func (p *Player) renderMap(layout *mapLayout, currentRoom *components.Room, world World) (string, error) {
+	view := p.viewMap(layout, world)
+
+	// only what the player knows of is drawn, or takes up space
+	coordByRoom := make(map[*components.Room]coord, len(layout.coordByRoom))
+	for r, c := range layout.coordByRoom {
+		if view.known[r] || view.glimpsed[r] {
+			coordByRoom[r] = c
+		}
+	}
 	if len(coordByRoom) == 0 {
 		return "", nil
 	}
 
 	minX, maxX, minY, maxY := 0, 0, 0, 0
+	first := true
 	for _, p := range coordByRoom {
+		if first {
+			minX, maxX, minY, maxY = p.X, p.X, p.Y, p.Y
+			first = false
+		}
 		if p.X < minX {
 			minX = p.X
 		}
@@ -146,24 +261,47 @@ func (p *Player) renderMap(coordByRoom map[*components.Room]coord, currentRoom *
 		}
 	}
 
+	used := map[string]bool{}
 	for r, c := range coordByRoom {
 		gx := (c.X - minX) * 2
 		gy := (c.Y - minY) * 2
 
+		if view.glimpsed[r] {
+			grid[gy][gx] = "?"
+			used["?"] = true
+			continue
+		}
+
 		if r == currentRoom {
 			grid[gy][gx] = fmt.Sprintf("%s%s%s", models.SGR["red"], "@", models.SGR["reset"])
+			used["@"] = true
 		} else if len(r.GetChildren().GetChildrenByAlias(p.trackingAlias)) > 0 {
 			grid[gy][gx] = fmt.Sprintf("%s%s%s", models.SGR["yellow"], "!", models.SGR["reset"])
+			used["!"] = true
 		} else {
+			// the ways up and down from landmarks aren't known yet
 			color := models.SGR[r.MapColor]
 			icon := r.MapIcon
+			if view.explored[r] {
+				icon = mapIcon(r)
+			}
 			grid[gy][gx] = fmt.Sprintf("%s%s%s", color, icon, models.SGR["reset"])
+			used[icon] = true
 		}
 
-		for _, roomId := range r.Exits {
-			roomEntity, ok := world.GetEntityById(roomId)
+		if !view.explored[r] {
+			continue
+		}
+
+		for _, exit := range r.VisibleExits() {
+			delta, ok := mapDelta(exit)
 			if !ok {
-				return "", fmt.Errorf("entity with id '%s' does not exist", roomId)
+				continue
+			}
+
+			roomEntity, ok := world.GetEntityById(exit.RoomId)
+			if !ok {
+				return "", fmt.Errorf("entity with id '%s' does not exist", exit.RoomId)
 			}
 
 			room, err := entities.RequireComponent[*components.Room](roomEntity)
@@ -171,33 +309,88 @@ func (p *Player) renderMap(coordByRoom map[*components.Room]coord, currentRoom *
 				return "", fmt.Errorf("render map: %w", err)
 			}
 
+			// exits leading somewhere other than next door twist away
+			symbol := connector(exit, delta)
 			npos, ok := coordByRoom[room]
-			if !ok {
+			switch {
+			case ok && npos.X-c.X == delta.X && npos.Y-c.Y == delta.Y:
+			case ok || layout.twisted[exit]:
+				symbol = "~"
+			default:
 				continue
 			}
-			dx := npos.X - c.X
-			dy := npos.Y - c.Y
-			if dx == 1 {
-				grid[gy][gx+1] = "-"
-			} else if dx == -1 {
-				grid[gy][gx-1] = "-"
-			} else if dy == 1 {
-				grid[gy+1][gx] = "|"
-			} else if dy == -1 {
-				grid[gy-1][gx] = "|"
+
+			x, y := gx+delta.X, gy+delta.Y
+			if y < 0 || y >= height || x < 0 || x >= width {
+				continue
 			}
+			grid[y][x] = symbol
+			used[symbol] = true
 		}
 	}
 
 	var b strings.Builder
 	b.Grow(height * (width + 1))
+	if currentRoom.Zone != "" {
+		b.WriteString(currentRoom.Zone)
+		b.WriteByte('\n')
+	}
 	for _, row := range grid {
 		b.WriteString(strings.Join(row, ""))
 		b.WriteByte('\n')
 	}
+
+	legend := []string{}
+	for _, entry := range mapLegend {
+		if used[entry.symbol] {
+			legend = append(legend, fmt.Sprintf("%s %s", entry.symbol, entry.meaning))
+		}
+	}
+	if len(legend) > 0 {
+		b.WriteString(strings.Join(legend, ", "))
+		b.WriteByte('\n')
+	}
+
 	return b.String(), nil
 }
 
+// mapIcon is drawn for r. Rooms without an icon of their own show whether
+// there's a way up or down from them.
+func mapIcon(r *components.Room) string {
+	if r.MapIcon != components.DefaultMapIcon {
+		return r.MapIcon
+	}
+
+	_, up := r.GetExit(models.DirectionUp)
+	_, down := r.GetExit(models.DirectionDown)
+	switch {
+	case up && down:
+		return "%"
+	case up:
+		return "^"
+	case down:
+		return "v"
+	}
+	return r.MapIcon
+}
+
+// connector is drawn between two rooms joined by exit. Closed doors show as
+// a plus on either side.
+func connector(exit *components.Exit, delta coord) string {
+	switch {
+	case exit.Closed:
+		return "+"
+	case delta.Y == 0:
+		return "-"
+	case delta.X == 0:
+		return "|"
+	case delta.X == delta.Y:
+		return "\\"
+	default:
+		return "/"
+	}
+}
+
 func (p *Player) Track(alias string) (string, error) {
 	p.trackingAlias = alias
 
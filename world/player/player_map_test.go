@@ -0,0 +1,236 @@
+package player
+
+import (
+	"testing"
+
+	"example.com/mud/parser/commands"
+	"example.com/mud/utils"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/components"
+	"github.com/stretchr/testify/require"
+)
+
+// mapWorld is just enough of a world to draw maps in
+type mapWorld struct {
+	World
+	rooms    map[string]*entities.Entity
+	revealed bool
+}
+
+func (w *mapWorld) GetEntityById(id string) (*entities.Entity, bool) {
+	e, ok := w.rooms[id]
+	return e, ok
+}
+
+func (w *mapWorld) MapRevealed() bool {
+	return w.revealed
+}
+
+// room makes a room with exits by direction to room ids, edited by each of
+// edits, e.g. to put it in a zone
+func room(exits map[string]string, edits ...func(*components.Room)) *components.Room {
+	r := components.NewRoom()
+	for direction, id := range exits {
+		r.Exits[direction] = components.NewExit(direction, id)
+	}
+	for _, edit := range edits {
+		edit(r)
+	}
+	return r
+}
+
+func zone(name string) func(*components.Room) {
+	return func(r *components.Room) { r.Zone = name }
+}
+
+func placed(x, y int) func(*components.Room) {
+	return func(r *components.Room) { r.X, r.Y, r.Placed = x, y, true }
+}
+
+func closed(direction string) func(*components.Room) {
+	return func(r *components.Room) { r.Exits[direction].Closed = true }
+}
+
+func newMapWorld(rooms map[string]*components.Room) *mapWorld {
+	w := &mapWorld{rooms: map[string]*entities.Entity{}, revealed: true}
+	for id, r := range rooms {
+		e := entities.NewEntity(id, "", nil, nil, nil, nil)
+		e.Id = id
+		w.rooms[id] = e.Add(r)
+	}
+	return w
+}
+
+// a town whose inn, past the chapel, would be drawn where the bakery is
+func town() map[string]*components.Room {
+	return map[string]*components.Room{
+		"Square": room(map[string]string{"east": "Market", "north": "Chapel", "west": "Gate"}, zone("Town")),
+		"Market": room(map[string]string{"north": "Bakery", "west": "Square"}, zone("Town")),
+		"Bakery": room(map[string]string{"south": "Market"}, zone("Town")),
+		"Chapel": room(map[string]string{"east": "Inn", "south": "Square"}, zone("Town")),
+		"Inn":    room(map[string]string{"west": "Chapel"}, zone("Town")),
+		"Gate":   room(map[string]string{"east": "Square"}, zone("Fields")),
+	}
+}
+
+// directions are registered globally, so these tests don't run in parallel
+
+func TestAssignCoordinates(t *testing.T) {
+	require.NoError(t, commands.ReplaceCommands(nil))
+
+	type tc struct {
+		name  string
+		rooms map[string]*components.Room
+		start string
+		want  map[string]coord
+		// exits drawn twisting away, by room and direction
+		twisted map[string]string
+	}
+
+	cases := []tc{
+		{
+			name: "a step from the room reached from",
+			rooms: map[string]*components.Room{
+				"Hall":    room(map[string]string{"east": "Kitchen"}),
+				"Kitchen": room(map[string]string{"north": "Larder", "west": "Hall"}),
+				"Larder":  room(map[string]string{"south": "Kitchen"}),
+			},
+			start: "Kitchen",
+			want:  map[string]coord{"Kitchen": {0, 0}, "Hall": {-1, 0}, "Larder": {0, -1}},
+		},
+		{
+			name: "where the builder placed them",
+			rooms: map[string]*components.Room{
+				"Landing": room(map[string]string{"east": "Closet"}, placed(2, 2)),
+				"Closet":  room(map[string]string{"west": "Landing"}, placed(5, 2)),
+			},
+			start: "Landing",
+			want:  map[string]coord{"Landing": {2, 2}, "Closet": {5, 2}},
+		},
+		{
+			name: "placed rooms reached from rooms that aren't",
+			rooms: map[string]*components.Room{
+				"Landing": room(map[string]string{"east": "Closet"}),
+				"Closet":  room(map[string]string{"west": "Landing"}, placed(5, 2)),
+			},
+			start: "Landing",
+			want:  map[string]coord{"Landing": {0, 0}, "Closet": {1, 0}},
+		},
+		{
+			name:    "only rooms in the same zone, and not over each other",
+			rooms:   town(),
+			start:   "Square",
+			want:    map[string]coord{"Square": {0, 0}, "Market": {1, 0}, "Bakery": {1, -1}, "Chapel": {0, -1}},
+			twisted: map[string]string{"Chapel": "east"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			w := newMapWorld(c.rooms)
+
+			layout, err := assignCoordinates(w.rooms[c.start], w, 5)
+			require.NoError(t, err)
+
+			got := map[string]coord{}
+			for r, co := range layout.coordByRoom {
+				got[layout.entityByRoom[r].Id] = co
+			}
+			require.Equal(t, c.want, got)
+
+			twisted := map[string]string{}
+			for id, r := range c.rooms {
+				for direction, exit := range r.Exits {
+					if layout.twisted[exit] {
+						twisted[id] = direction
+					}
+				}
+			}
+			if c.twisted == nil {
+				c.twisted = map[string]string{}
+			}
+			require.Equal(t, c.twisted, twisted)
+		})
+	}
+}
+
+func TestRenderMap(t *testing.T) {
+	require.NoError(t, commands.ReplaceCommands(nil))
+
+	type tc struct {
+		name     string
+		rooms    map[string]*components.Room
+		start    string
+		revealed bool
+		want     string
+	}
+
+	cases := []tc{
+		{
+			name: "rooms joined by exits",
+			rooms: map[string]*components.Room{
+				"Hall":    room(map[string]string{"east": "Kitchen", "southeast": "Yard"}),
+				"Kitchen": room(map[string]string{"west": "Hall"}),
+				"Yard":    room(map[string]string{"northwest": "Hall"}),
+			},
+			start:    "Hall",
+			revealed: true,
+			want:     "@-O\n \\ \n  O\n@ you\n",
+		},
+		{
+			name:     "the zone above, and no other zones",
+			rooms:    town(),
+			start:    "Square",
+			revealed: true,
+			want:     "Town\nO~O\n| |\n@-O\n@ you, ~ twisting passage\n",
+		},
+		{
+			name: "placed rooms further off than next door",
+			rooms: map[string]*components.Room{
+				"Landing": room(map[string]string{"north": "Study", "east": "Closet"}, zone("Tower"), placed(0, 0)),
+				"Study":   room(nil, zone("Tower"), placed(0, -1)),
+				"Closet":  room(nil, zone("Tower"), placed(3, 0)),
+			},
+			start:    "Landing",
+			revealed: true,
+			want:     "Tower\nO      \n|      \n@~    O\n@ you, ~ twisting passage\n",
+		},
+		{
+			name: "ways up and down, and closed doors",
+			rooms: map[string]*components.Room{
+				"Hall":   room(map[string]string{"east": "Stairs", "west": "Vault"}, closed("west")),
+				"Stairs": room(map[string]string{"west": "Hall", "up": "Attic", "down": "Cellar"}),
+				"Vault":  room(map[string]string{"east": "Hall", "down": "Cellar"}, closed("east")),
+				"Attic":  room(map[string]string{"down": "Stairs"}),
+				"Cellar": room(map[string]string{"up": "Stairs"}),
+			},
+			start:    "Hall",
+			revealed: true,
+			want:     "v+@-%\n@ you, v down, % up and down, + closed door\n",
+		},
+		{
+			name: "unexplored rooms next door",
+			rooms: map[string]*components.Room{
+				"Hall":    room(map[string]string{"east": "Kitchen"}),
+				"Kitchen": room(map[string]string{"east": "Larder", "west": "Hall"}),
+				"Larder":  room(map[string]string{"west": "Kitchen"}),
+			},
+			start: "Hall",
+			want:  "@-?\n@ you, ? unexplored\n",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			w := newMapWorld(c.rooms)
+			w.revealed = c.revealed
+
+			start := w.rooms[c.start]
+			p := NewActor(entities.NewEntity("Alice", "", nil, nil, nil, nil), w, start)
+
+			got, err := p.Map()
+			require.NoError(t, err)
+			require.Equal(t, c.want, utils.StripANSI(got))
+		})
+	}
+}
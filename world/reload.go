@@ -0,0 +1,195 @@
+package world
+
+import (
+	"fmt"
+
+	"example.com/mud/dsl"
+	"example.com/mud/models"
+	"example.com/mud/parser/commands"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/components"
+)
+
+// ReloadResult counts what a reload changed.
+type ReloadResult struct {
+	Updated  int
+	Added    int
+	Removed  int
+	Commands int
+}
+
+func (r *ReloadResult) String() string {
+	return fmt.Sprintf("%d entities updated, %d added, %d no longer defined, %d commands loaded.", r.Updated, r.Added, r.Removed, r.Commands)
+}
+
+// Reload compiles the definitions in DataDirectory again and applies them to
+// the running world. Descriptions, aliases, reactions, room exits and commands
+// are replaced, while players, inventories and fields stay as they are. If
+// the definitions fail to compile, the world is left untouched.
+func (w *World) Reload() (*ReloadResult, error) {
+	if w.DataDirectory == "" {
+		return nil, fmt.Errorf("reload: no data directory configured")
+	}
+
+	prototypes, cmds, err := dsl.LoadEntitiesFromDirectory(w.DataDirectory)
+	if err != nil {
+		return nil, fmt.Errorf("reload: %w", err)
+	}
+
+	var result *ReloadResult
+	w.Do(func() {
+		result, err = w.reload(prototypes, cmds)
+	})
+	return result, err
+}
+
+// apply freshly compiled definitions, on the world's loop so nothing runs
+// while the world is being rewired
+func (w *World) reload(prototypes map[string]*entities.Entity, cmds []*models.CommandDefinition) (*ReloadResult, error) {
+	// commands are only put in place once every entity has been updated
+	commandSet, err := commands.BuildCommands(cmds)
+	if err != nil {
+		return nil, fmt.Errorf("reload: %w", err)
+	}
+
+	result := &ReloadResult{
+		Commands: len(cmds),
+	}
+
+	players := make(map[*entities.Entity]struct{}, len(w.players))
+	for _, p := range w.players {
+		players[p.Entity] = struct{}{}
+	}
+
+	for _, e := range w.liveEntities() {
+		prototype, ok := prototypes[e.Id]
+		if !ok {
+			continue
+		}
+
+		_, isPlayer := players[e]
+		if err := reloadEntity(e, prototype, isPlayer); err != nil {
+			return nil, fmt.Errorf("reload: %w", err)
+		}
+		result.Updated++
+	}
+
+	for id, prototype := range prototypes {
+		if _, ok := w.entityMap[id]; !ok {
+			w.entityMap[id] = prototype
+			result.Added++
+		}
+	}
+	for id := range w.entityMap {
+		if _, ok := prototypes[id]; !ok {
+			result.Removed++
+		}
+	}
+
+	commandSet.Install()
+	w.startAllBehaviors()
+
+	return result, nil
+}
+
+// liveEntities returns every entity in the world, including children and players.
+func (w *World) liveEntities() []*entities.Entity {
+	var all []*entities.Entity
+	seen := map[*entities.Entity]struct{}{}
+
+	var walk func(e *entities.Entity)
+	walk = func(e *entities.Entity) {
+		if _, ok := seen[e]; ok {
+			return
+		}
+		seen[e] = struct{}{}
+		all = append(all, e)
+
+		for _, cwc := range e.GetComponentsWithChildren() {
+			for _, child := range cwc.GetChildren().GetChildren() {
+				walk(child)
+			}
+		}
+	}
+
+	for _, e := range w.entityMap {
+		walk(e)
+	}
+
+	return all
+}
+
+// reloadEntity updates a live entity from its freshly compiled prototype.
+// Players keep their own name, description and aliases.
+func reloadEntity(e, prototype *entities.Entity, isPlayer bool) error {
+	if !isPlayer {
+		e.Description = prototype.Description
+
+		aliases := append([]string(nil), prototype.Aliases...)
+		if e.Parent != nil {
+			aliasesValue, err := models.VList(aliases)
+			if err != nil {
+				return fmt.Errorf("reload entity '%s': %w", e.Name, err)
+			}
+			if err := e.SetField("aliases", aliasesValue); err != nil {
+				return fmt.Errorf("reload entity '%s': %w", e.Name, err)
+			}
+		} else {
+			e.Aliases = aliases
+		}
+	}
+
+	// fields may have changed at runtime, only new ones are picked up
+	for k, v := range prototype.Fields {
+		if _, ok := e.Fields[k]; !ok {
+			e.Fields[k] = v
+		}
+	}
+
+	newEventful, hasNew := entities.GetComponent[*components.Eventful](prototype)
+	oldEventful, hasOld := entities.GetComponent[*components.Eventful](e)
+	switch {
+	case hasNew && hasOld:
+		oldEventful.Rules = newEventful.Rules
+	case hasNew:
+		e.Add(newEventful.Copy())
+	case hasOld:
+		oldEventful.Rules = map[string][]*entities.Rule{}
+	}
+
+	newRoom, hasNew := entities.GetComponent[*components.Room](prototype)
+	oldRoom, hasOld := entities.GetComponent[*components.Room](e)
+	if hasNew && hasOld {
+		oldRoom.MapIcon = newRoom.MapIcon
+		oldRoom.MapColor = newRoom.MapColor
+		oldRoom.Zone = newRoom.Zone
+		oldRoom.X, oldRoom.Y, oldRoom.Placed = newRoom.X, newRoom.Y, newRoom.Placed
+		oldRoom.Landmark = newRoom.Landmark
+
+		// doors stay open or shut, and revealed exits revealed, like fields
+		for direction, exit := range newRoom.Exits {
+			if old, ok := oldRoom.Exits[direction]; ok {
+				hidden := exit.Hidden && old.Hidden
+				exit.ApplyState(old.State())
+				exit.Hidden = hidden
+			}
+		}
+		oldRoom.Exits = newRoom.Exits
+	}
+
+	// a changed tick is picked up from the next one, see startTicking
+	newBehavior, hasNew := entities.GetComponent[*components.Behavior](prototype)
+	oldBehavior, hasOld := entities.GetComponent[*components.Behavior](e)
+	switch {
+	case hasNew && hasOld:
+		oldBehavior.Tick = newBehavior.Tick
+		oldBehavior.Wander = newBehavior.Wander
+	case hasNew:
+		e.Add(newBehavior.Copy())
+	case hasOld:
+		oldBehavior.Tick = 0
+		oldBehavior.Wander = 0
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is where a Scheduler gets the time from, and waits on.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+// RealClock is the wall clock.
+var RealClock Clock = realClock{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// FakeClock only moves when it is told to, so tests (or a replay) can step
+// through time deterministically.
+//
+// Schedulers using a FakeClock don't run jobs on their own. Instead, every
+// job that comes due while the clock is advanced is run, in order, before
+// Advance returns.
+type FakeClock struct {
+	mu         sync.Mutex
+	now        time.Time
+	waiters    []*fakeWaiter
+	schedulers []*Scheduler
+}
+
+var _ Clock = &FakeClock{}
+
+type fakeWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{now: start}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After returns a channel that receives the time once the clock has been
+// advanced by d.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	w := &fakeWaiter{at: c.now.Add(d), ch: make(chan time.Time, 1)}
+	if !w.at.After(c.now) {
+		w.ch <- c.now
+		return w.ch
+	}
+	c.waiters = append(c.waiters, w)
+	return w.ch
+}
+
+// Advance moves the clock forward by d, running due jobs as it goes.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.Set(c.Now().Add(d))
+}
+
+// Set moves the clock forward to t, running every job due by then in the
+// order they're due. Each job sees the clock at the time it was due, so
+// jobs it schedules in turn land where they would with a real clock. Time
+// never moves backwards, so a t in the past does nothing.
+func (c *FakeClock) Set(t time.Time) {
+	for {
+		c.mu.Lock()
+		s, due, ok := c.nextDue(t)
+		if !ok {
+			if t.After(c.now) {
+				c.now = t
+			}
+			c.fireWaiters()
+			c.mu.Unlock()
+			return
+		}
+
+		if due.After(c.now) {
+			c.now = due
+		}
+		c.fireWaiters()
+		c.mu.Unlock()
+
+		s.runNext(due)
+	}
+}
+
+// the scheduler with the earliest job due by t, ties go to the scheduler
+// attached first
+func (c *FakeClock) nextDue(t time.Time) (*Scheduler, time.Time, bool) {
+	var next *Scheduler
+	var nextRun time.Time
+
+	for _, s := range c.schedulers {
+		run, ok := s.peek()
+		if !ok || run.After(t) {
+			continue
+		}
+		if next == nil || run.Before(nextRun) {
+			next, nextRun = s, run
+		}
+	}
+
+	return next, nextRun, next != nil
+}
+
+func (c *FakeClock) fireWaiters() {
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			pending = append(pending, w)
+			continue
+		}
+		w.ch <- c.now
+	}
+	c.waiters = pending
+}
+
+func (c *FakeClock) attach(s *Scheduler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.schedulers = append(c.schedulers, s)
+}
+
+// when the earliest job is due
+func (s *Scheduler) peek() (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.jobs) == 0 {
+		return time.Time{}, false
+	}
+	return s.jobs[0].NextRun, true
+}
+
+// run the earliest job on the calling goroutine, if it's due by t
+func (s *Scheduler) runNext(t time.Time) {
+	job, ok := s.popDue(t)
+	if !ok {
+		return
+	}
+
+	select {
+	case <-s.quit:
+		// stopped schedulers don't run anything
+		s.mu.Lock()
+		delete(s.active, job)
+		s.mu.Unlock()
+		return
+	default:
+	}
+
+	s.runJob(job)
+}
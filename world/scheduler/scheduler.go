@@ -2,13 +2,48 @@ package scheduler
 
 import (
 	"container/heap"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type Job struct {
+	// given by the scheduler when the job is first added
+	Id int
+
 	NextRun time.Time
 	RunFunc func()
+
+	// what the job is for, e.g. "repeat every 5 seconds"
+	Label string
+	// the entity the job belongs to, compared by identity. Jobs are
+	// cancelled along with their owner
+	Owner any
+	// describes what scheduled the job, e.g. so it can be saved. The
+	// scheduler doesn't look at it
+	Data any
+
+	cancelled atomic.Bool
+}
+
+// Cancel stops the job from running again. Jobs already running finish, but
+// aren't scheduled again.
+func (j *Job) Cancel() {
+	j.cancelled.Store(true)
+}
+
+func (j *Job) Cancelled() bool {
+	return j.cancelled.Load()
+}
+
+// JobInfo describes a pending job, for listing.
+type JobInfo struct {
+	Id      int
+	NextRun time.Time
+	Label   string
+	Owner   any
+	Data    any
 }
 
 type Scheduler struct {
@@ -16,22 +51,81 @@ type Scheduler struct {
 	jobs JobHeap
 	wake chan struct{}
 	quit chan struct{}
+
+	// closed once the run loop exits
+	done     chan struct{}
+	stopOnce sync.Once
+
+	clock Clock
+
+	lastId int
+	// jobs popped off the heap that are running right now
+	active map[*Job]struct{}
+	// runs every job, so the world can wrap them
+	runner func(job *Job)
 }
 
 func NewScheduler() *Scheduler {
+	return NewSchedulerWithClock(RealClock)
+}
+
+// NewSchedulerWithClock creates a scheduler running jobs by clock's time.
+// With a FakeClock, jobs only run as the clock is advanced.
+func NewSchedulerWithClock(clock Clock) *Scheduler {
 	s := &Scheduler{
-		jobs: make(JobHeap, 0),
-		wake: make(chan struct{}, 1),
-		quit: make(chan struct{}),
+		jobs:   make(JobHeap, 0),
+		wake:   make(chan struct{}, 1),
+		quit:   make(chan struct{}),
+		done:   make(chan struct{}),
+		clock:  clock,
+		active: make(map[*Job]struct{}),
 	}
 	heap.Init(&s.jobs)
+
+	if fake, ok := clock.(*FakeClock); ok {
+		fake.attach(s)
+		close(s.done)
+		return s
+	}
+
 	go s.run()
 	return s
 }
 
+// Now is the time jobs should be scheduled relative to.
+func (s *Scheduler) Now() time.Time {
+	return s.clock.Now()
+}
+
+// Pending is how many jobs are waiting to run.
+func (s *Scheduler) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.jobs)
+}
+
+// SetRunner makes every job run through runner, which must call job.RunFunc.
+func (s *Scheduler) SetRunner(runner func(job *Job)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runner = runner
+}
+
+// Add schedules job to run at job.NextRun. The same job may be added again
+// once it has run, e.g. to repeat it, and keeps its id. Cancelled jobs are
+// never added.
 func (s *Scheduler) Add(job *Job) {
+	if job.Cancelled() {
+		return
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
+
+	if job.Id == 0 {
+		s.lastId++
+		job.Id = s.lastId
+	}
 	heap.Push(&s.jobs, job)
 
 	select {
@@ -40,7 +134,118 @@ func (s *Scheduler) Add(job *Job) {
 	} // wake the loop upon jobs updating
 }
 
+// Cancel cancels the job with id, returning whether there was one.
+func (s *Scheduler) Cancel(id int) bool {
+	return s.cancelWhere(func(job *Job) bool {
+		return job.Id == id
+	}) > 0
+}
+
+// CancelOwnedBy cancels every job belonging to owner, or only those with
+// label if it isn't empty, returning how many were cancelled.
+func (s *Scheduler) CancelOwnedBy(owner any, label string) int {
+	if owner == nil {
+		return 0
+	}
+
+	return s.cancelWhere(func(job *Job) bool {
+		return job.Owner == owner && (label == "" || job.Label == label)
+	})
+}
+
+func (s *Scheduler) cancelWhere(match func(job *Job) bool) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cancelled := 0
+	kept := s.jobs[:0]
+	for _, job := range s.jobs {
+		if match(job) {
+			job.Cancel()
+			cancelled++
+			continue
+		}
+		kept = append(kept, job)
+	}
+	s.jobs = kept
+	heap.Init(&s.jobs)
+
+	// running jobs won't be added back
+	for job := range s.active {
+		if match(job) && !job.Cancelled() {
+			job.Cancel()
+			cancelled++
+		}
+	}
+
+	return cancelled
+}
+
+// Jobs lists every pending job, soonest first.
+func (s *Scheduler) Jobs() []JobInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	infos := make([]JobInfo, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		infos = append(infos, JobInfo{
+			Id:      job.Id,
+			NextRun: job.NextRun,
+			Label:   job.Label,
+			Owner:   job.Owner,
+			Data:    job.Data,
+		})
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		if !infos[i].NextRun.Equal(infos[j].NextRun) {
+			return infos[i].NextRun.Before(infos[j].NextRun)
+		}
+		return infos[i].Id < infos[j].Id
+	})
+	return infos
+}
+
+// pop the earliest job if it's due by t, marking it active
+func (s *Scheduler) popDue(t time.Time) (*Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.jobs) == 0 || s.jobs[0].NextRun.After(t) {
+		return nil, false
+	}
+
+	job := heap.Pop(&s.jobs).(*Job)
+	s.active[job] = struct{}{}
+	return job, true
+}
+
+// run job through the runner, unless it was cancelled while waiting
+func (s *Scheduler) runJob(job *Job) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.active, job)
+		s.mu.Unlock()
+	}()
+
+	if job.Cancelled() {
+		return
+	}
+
+	s.mu.Lock()
+	runner := s.runner
+	s.mu.Unlock()
+
+	if runner != nil {
+		runner(job)
+	} else {
+		job.RunFunc()
+	}
+}
+
 func (s *Scheduler) run() {
+	defer close(s.done)
+
 	for {
 		s.mu.Lock()
 		if len(s.jobs) == 0 {
@@ -54,13 +259,13 @@ func (s *Scheduler) run() {
 		}
 
 		next := s.jobs[0]
-		now := time.Now()
+		now := s.clock.Now()
 		wait := next.NextRun.Sub(now)
 		s.mu.Unlock()
 
 		if wait > 0 {
 			select {
-			case <-time.After(wait):
+			case <-s.clock.After(wait):
 			case <-s.wake:
 				continue
 			case <-s.quit:
@@ -68,14 +273,22 @@ func (s *Scheduler) run() {
 			}
 		}
 
-		s.mu.Lock()
-		next = heap.Pop(&s.jobs).(*Job)
-		s.mu.Unlock()
+		job, ok := s.popDue(s.clock.Now())
+		if !ok {
+			// cancelled while waiting
+			continue
+		}
 
-		go next.RunFunc()
+		// one at a time and in order, the runner decides where they run
+		s.runJob(job)
 	}
 }
 
+// Stop stops running new jobs, and waits for the one already running to finish.
 func (s *Scheduler) Stop() {
-	close(s.quit)
+	s.stopOnce.Do(func() {
+		close(s.quit)
+	})
+
+	<-s.done
 }
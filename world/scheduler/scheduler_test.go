@@ -0,0 +1,282 @@
+// scheduler/scheduler_test.go
+package scheduler
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var start = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+func TestFakeClock_RunsDueJobsInOrder(t *testing.T) {
+	t.Parallel()
+
+	type tc struct {
+		name    string
+		delays  []time.Duration
+		advance []time.Duration
+		want    []time.Duration
+		pending int
+	}
+
+	cases := []tc{
+		{
+			name:    "nothing due",
+			delays:  []time.Duration{5 * time.Second},
+			advance: []time.Duration{4 * time.Second},
+			want:    nil,
+			pending: 1,
+		},
+		{
+			name:    "due exactly",
+			delays:  []time.Duration{5 * time.Second},
+			advance: []time.Duration{5 * time.Second},
+			want:    []time.Duration{5 * time.Second},
+		},
+		{
+			name:    "added out of order, run in order",
+			delays:  []time.Duration{3 * time.Second, time.Second, 2 * time.Second},
+			advance: []time.Duration{10 * time.Second},
+			want:    []time.Duration{time.Second, 2 * time.Second, 3 * time.Second},
+		},
+		{
+			name:    "several advances",
+			delays:  []time.Duration{time.Second, 3 * time.Second, 6 * time.Second},
+			advance: []time.Duration{2 * time.Second, 2 * time.Second},
+			want:    []time.Duration{time.Second, 3 * time.Second},
+			pending: 1,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			clock := NewFakeClock(start)
+			s := NewSchedulerWithClock(clock)
+
+			var ran []time.Duration
+			for _, d := range c.delays {
+				s.Add(&Job{
+					NextRun: start.Add(d),
+					RunFunc: func() {
+						// jobs see the time they were due at
+						ran = append(ran, clock.Now().Sub(start))
+					},
+				})
+			}
+
+			for _, d := range c.advance {
+				clock.Advance(d)
+			}
+
+			require.Equal(t, c.want, ran)
+			require.Equal(t, c.pending, s.Pending())
+		})
+	}
+}
+
+func TestFakeClock_JobsScheduledByJobs(t *testing.T) {
+	t.Parallel()
+
+	clock := NewFakeClock(start)
+	s := NewSchedulerWithClock(clock)
+
+	// a job rescheduling itself every second, like schedule repeating
+	var ran []time.Time
+	var every func()
+	every = func() {
+		ran = append(ran, clock.Now())
+		s.Add(&Job{NextRun: clock.Now().Add(time.Second), RunFunc: every})
+	}
+	s.Add(&Job{NextRun: start.Add(time.Second), RunFunc: every})
+
+	clock.Advance(3500 * time.Millisecond)
+
+	require.Equal(t, []time.Time{
+		start.Add(time.Second),
+		start.Add(2 * time.Second),
+		start.Add(3 * time.Second),
+	}, ran)
+	require.Equal(t, start.Add(3500*time.Millisecond), clock.Now())
+}
+
+func TestFakeClock_After(t *testing.T) {
+	t.Parallel()
+
+	clock := NewFakeClock(start)
+	after := clock.After(time.Minute)
+
+	clock.Advance(59 * time.Second)
+	select {
+	case <-after:
+		t.Fatal("fired early")
+	default:
+	}
+
+	clock.Advance(time.Second)
+	select {
+	case now := <-after:
+		require.Equal(t, start.Add(time.Minute), now)
+	default:
+		t.Fatal("did not fire")
+	}
+
+	// setting the clock backwards does nothing
+	clock.Set(start)
+	require.Equal(t, start.Add(time.Minute), clock.Now())
+}
+
+func TestScheduler_RealClock(t *testing.T) {
+	t.Parallel()
+
+	s := NewScheduler()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	s.Add(&Job{NextRun: s.Now().Add(10 * time.Millisecond), RunFunc: wg.Done})
+	wg.Wait()
+
+	// stopped schedulers don't run what's left
+	ran := false
+	s.Add(&Job{NextRun: s.Now().Add(time.Hour), RunFunc: func() { ran = true }})
+	s.Stop()
+	require.False(t, ran)
+}
+
+func TestScheduler_Cancel(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := &struct{ name string }{"alice"}, &struct{ name string }{"bob"}
+
+	type tc struct {
+		name   string
+		cancel func(s *Scheduler) int
+		want   []string
+	}
+
+	cases := []tc{
+		{
+			name: "by id",
+			cancel: func(s *Scheduler) int {
+				if s.Cancel(2) {
+					return 1
+				}
+				return 0
+			},
+			want: []string{"alice tick", "bob tick"},
+		},
+		{
+			name: "unknown id",
+			cancel: func(s *Scheduler) int {
+				if s.Cancel(99) {
+					return 1
+				}
+				return 0
+			},
+			want: []string{"alice tick", "alice tock", "bob tick"},
+		},
+		{
+			name: "everything owned",
+			cancel: func(s *Scheduler) int {
+				return s.CancelOwnedBy(alice, "")
+			},
+			want: []string{"bob tick"},
+		},
+		{
+			name: "owned with label",
+			cancel: func(s *Scheduler) int {
+				return s.CancelOwnedBy(alice, "tick")
+			},
+			want: []string{"alice tock", "bob tick"},
+		},
+		{
+			name: "nobody",
+			cancel: func(s *Scheduler) int {
+				return s.CancelOwnedBy(nil, "")
+			},
+			want: []string{"alice tick", "alice tock", "bob tick"},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			clock := NewFakeClock(start)
+			s := NewSchedulerWithClock(clock)
+
+			var ran []string
+			add := func(owner *struct{ name string }, label string, d time.Duration) {
+				s.Add(&Job{
+					NextRun: start.Add(d),
+					Label:   label,
+					Owner:   owner,
+					RunFunc: func() { ran = append(ran, owner.name+" "+label) },
+				})
+			}
+			add(alice, "tick", time.Second)
+			add(alice, "tock", 2*time.Second)
+			add(bob, "tick", 3*time.Second)
+
+			cancelled := c.cancel(s)
+			require.Equal(t, 3-len(c.want), cancelled)
+			require.Equal(t, len(c.want), s.Pending())
+
+			clock.Advance(time.Minute)
+			require.Equal(t, c.want, ran)
+		})
+	}
+}
+
+func TestScheduler_CancelRepeatingJob(t *testing.T) {
+	t.Parallel()
+
+	clock := NewFakeClock(start)
+	s := NewSchedulerWithClock(clock)
+
+	// the same job added again after each run, cancelled while running
+	owner := &struct{}{}
+	runs := 0
+	job := &Job{NextRun: start.Add(time.Second), Label: "every second", Owner: owner}
+	job.RunFunc = func() {
+		runs++
+		if runs == 3 {
+			require.Equal(t, 1, s.CancelOwnedBy(owner, ""))
+		}
+		job.NextRun = job.NextRun.Add(time.Second)
+		s.Add(job)
+	}
+	s.Add(job)
+	id := job.Id
+
+	clock.Advance(10 * time.Second)
+	require.Equal(t, 3, runs)
+	require.Equal(t, id, job.Id, "jobs keep their id when added again")
+	require.True(t, job.Cancelled())
+	require.Zero(t, s.Pending())
+}
+
+func TestScheduler_Jobs(t *testing.T) {
+	t.Parallel()
+
+	clock := NewFakeClock(start)
+	s := NewSchedulerWithClock(clock)
+
+	owner := &struct{}{}
+	s.Add(&Job{NextRun: start.Add(3 * time.Second), Label: "later", RunFunc: func() {}})
+	s.Add(&Job{NextRun: start.Add(time.Second), Label: "soon", Owner: owner, RunFunc: func() {}})
+
+	require.Equal(t, []JobInfo{
+		{Id: 2, NextRun: start.Add(time.Second), Label: "soon", Owner: owner},
+		{Id: 1, NextRun: start.Add(3 * time.Second), Label: "later"},
+	}, s.Jobs())
+
+	clock.Advance(2 * time.Second)
+	require.Len(t, s.Jobs(), 1)
+}
@@ -0,0 +1,113 @@
+package world
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"example.com/mud/utils"
+	"example.com/mud/world/entities"
+)
+
+// Snapshot is the runtime state of every top-level entity in the world, and
+// the jobs they have scheduled. Connected players are left out, they are
+// saved with their accounts.
+type Snapshot struct {
+	SavedAt  time.Time                        `json:"savedAt"`
+	Entities map[string]*entities.EntityState `json:"entities"`
+	Jobs     []*JobState                      `json:"jobs,omitempty"`
+}
+
+// Snapshot captures the world's state. It must be called from the world's
+// loop, see Do.
+func (w *World) Snapshot() *Snapshot {
+	exclude := make(map[*entities.Entity]struct{}, len(w.players))
+	for _, p := range w.players {
+		exclude[p.Entity] = struct{}{}
+	}
+
+	refs, jobs := w.saveJobs(exclude)
+
+	snapshot := &Snapshot{
+		SavedAt:  time.Now(),
+		Entities: make(map[string]*entities.EntityState, len(w.entityMap)),
+		Jobs:     jobs,
+	}
+	for id, e := range w.entityMap {
+		snapshot.Entities[id] = e.StateRefs(exclude, refs)
+	}
+
+	return snapshot
+}
+
+// SaveSnapshot writes a snapshot of the world to path.
+func (w *World) SaveSnapshot(path string) error {
+	var snapshot *Snapshot
+	w.Do(func() {
+		snapshot = w.Snapshot()
+	})
+
+	return writeSnapshot(path, snapshot)
+}
+
+func writeSnapshot(path string, snapshot *Snapshot) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("save snapshot: %w", err)
+	}
+
+	if err := utils.WriteFileAtomic(path, data); err != nil {
+		return fmt.Errorf("save snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// LoadSnapshot reads a snapshot written by SaveSnapshot. A missing file
+// returns an error matching os.ErrNotExist.
+func LoadSnapshot(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("load snapshot: %w", err)
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("load snapshot: %w", err)
+	}
+
+	return &snapshot, nil
+}
+
+// Restore applies a snapshot to the world, and schedules its jobs again,
+// following MissedJobs for any that came due since it was saved. It is meant
+// to be called on boot, before any players have connected.
+func (w *World) Restore(snapshot *Snapshot) error {
+	var err error
+	w.Do(func() {
+		err = w.restore(snapshot)
+	})
+	return err
+}
+
+func (w *World) restore(snapshot *Snapshot) error {
+	refs := make(map[int]*entities.Entity)
+	for id, state := range snapshot.Entities {
+		e, ok := w.entityMap[id]
+		if !ok {
+			// the entity was removed from the definitions since saving
+			log.Printf("restore snapshot: skipping unknown entity '%s'", id)
+			continue
+		}
+
+		if err := e.ApplyStateRefs(state, w.entityMap, refs); err != nil {
+			return fmt.Errorf("restore snapshot: %w", err)
+		}
+	}
+	w.restoreJobs(snapshot.Jobs, refs)
+	w.startAllBehaviors()
+
+	return nil
+}
@@ -0,0 +1,212 @@
+package world
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/components"
+	"example.com/mud/world/player"
+	"example.com/mud/world/scheduler"
+)
+
+// how many rooms away a trail can be followed, so searches stay quick
+const maxPathDepth = 50
+
+// used between steps when the world isn't given a TravelStep
+const defaultTravelStep = time.Second
+
+// label of the job walking a travelling player
+const travelLabel = "travel"
+
+// pathStep is how a room was first reached while searching for a path.
+type pathStep struct {
+	from      *entities.Entity
+	direction string
+}
+
+// findPath finds the fewest directions e has to walk from room to reach a
+// room goal accepts. Locked exits, and exits whose conditions don't let e
+// through, aren't taken. Closed doors are, as travellers open them.
+func (w *World) findPath(e, room *entities.Entity, goal func(room *entities.Entity) bool) ([]string, bool) {
+	if goal(room) {
+		return []string{}, true
+	}
+
+	cameFrom := map[*entities.Entity]pathStep{room: {}}
+	frontier := []*entities.Entity{room}
+	for depth := 0; depth < maxPathDepth && len(frontier) > 0; depth++ {
+		next := []*entities.Entity{}
+		for _, current := range frontier {
+			rm, ok := entities.GetComponent[*components.Room](current)
+			if !ok {
+				continue
+			}
+
+			for _, exit := range rm.VisibleExits() {
+				neighbor, ok := w.entityMap[exit.RoomId]
+				if !ok {
+					continue
+				}
+				if _, seen := cameFrom[neighbor]; seen {
+					continue
+				}
+				if !w.passable(e, current, exit, neighbor) {
+					continue
+				}
+
+				cameFrom[neighbor] = pathStep{from: current, direction: exit.Direction}
+				if goal(neighbor) {
+					return walkBack(cameFrom, room, neighbor), true
+				}
+				next = append(next, neighbor)
+			}
+		}
+		frontier = next
+	}
+
+	return nil, false
+}
+
+// walkBack lists the directions leading from start to end.
+func walkBack(cameFrom map[*entities.Entity]pathStep, start, end *entities.Entity) []string {
+	path := []string{}
+	for room := end; room != start; room = cameFrom[room].from {
+		path = append([]string{cameFrom[room].direction}, path...)
+	}
+	return path
+}
+
+// passable reports whether e could go through exit from room to newRoom,
+// given the door is opened if it is closed.
+func (w *World) passable(e, room *entities.Entity, exit *components.Exit, newRoom *entities.Entity) bool {
+	if exit.Locked {
+		return false
+	}
+
+	allowed, err := exit.Allows(w.moveEvent(e, room, newRoom))
+	return err == nil && allowed
+}
+
+// roomNamed reports whether room goes by name, as its id, name or an alias.
+func roomNamed(room *entities.Entity, name string) bool {
+	if _, ok := entities.GetComponent[*components.Room](room); !ok {
+		return false
+	}
+	if strings.EqualFold(room.Id, name) || strings.EqualFold(room.Name, name) {
+		return true
+	}
+	for _, alias := range room.Aliases {
+		if strings.EqualFold(alias, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// trackCommand marks rooms with alias on the player's map, and points them
+// toward the nearest one, or toward a room by that name.
+func (w *World) trackCommand(p *player.Player, alias string) (string, error) {
+	message, err := p.Track(alias)
+	if err != nil {
+		return "", err
+	}
+
+	path, ok := w.findPath(p.Entity, p.CurrentRoom, func(room *entities.Entity) bool {
+		if roomNamed(room, alias) {
+			return true
+		}
+		rm, ok := entities.GetComponent[*components.Room](room)
+		return ok && len(rm.GetChildren().GetChildrenByAlias(alias)) > 0
+	})
+	switch {
+	case !ok:
+		return fmt.Sprintf("%s You can't find a trail to %s.", message, alias), nil
+	case len(path) == 0:
+		return fmt.Sprintf("%s The trail ends here.", message), nil
+	}
+	return fmt.Sprintf("%s The trail leads %s.", message, path[0]), nil
+}
+
+// travelCommand walks the player to the nearest room by the name of
+// destination, a step at a time.
+func (w *World) travelCommand(p *player.Player, destination string) (string, error) {
+	path, ok := w.findPath(p.Entity, p.CurrentRoom, func(room *entities.Entity) bool {
+		return roomNamed(room, destination)
+	})
+	switch {
+	case !ok:
+		return fmt.Sprintf("You don't know the way to %s.", destination), nil
+	case len(path) == 0:
+		return "You are already there.", nil
+	}
+
+	// only one journey at a time
+	w.Scheduler.CancelOwnedBy(p.Entity, travelLabel)
+	w.travelStep(p, path)
+
+	return fmt.Sprintf("You set off toward %s.", destination), nil
+}
+
+// travelStep schedules the player's next step along path, opening closed
+// doors on the way and stopping if the way turns out to be shut, or if the
+// player has gone somewhere else in the meantime. Each step leaves the player
+// catching their breath as if they had typed it. The journey is cancelled
+// with the player's other jobs when they disconnect.
+func (w *World) travelStep(p *player.Player, path []string) {
+	step := w.TravelStep
+	if step <= 0 {
+		step = defaultTravelStep
+	}
+	from := p.CurrentRoom
+
+	w.Scheduler.Add(&scheduler.Job{
+		NextRun: w.Scheduler.Now().Add(step),
+		Label:   travelLabel,
+		Owner:   p.Entity,
+		RunFunc: func() {
+			if w.playerFor(p.Entity) != p {
+				return
+			}
+			if p.CurrentRoom != from {
+				w.PublishTo(p.CurrentRoom, p.Entity, "You stop travelling.")
+				return
+			}
+
+			w.openOnTheWay(p, path[0])
+
+			refusal, err := w.MoveEntity(p.Entity, path[0])
+			if err != nil {
+				w.PublishTo(p.CurrentRoom, p.Entity, fmt.Sprintf("You stop travelling: %v", err))
+				return
+			}
+			if refusal != "" {
+				w.PublishTo(p.CurrentRoom, p.Entity, fmt.Sprintf("%s You stop travelling.", refusal))
+				return
+			}
+
+			p.StartCooldown(step)
+
+			if len(path) > 1 {
+				w.travelStep(p, path[1:])
+			}
+		},
+	})
+}
+
+// openOnTheWay opens the door the player is about to go through, if it is
+// closed, just as if they had typed open. Locked doors are left for the move
+// to refuse.
+func (w *World) openOnTheWay(p *player.Player, direction string) {
+	room, ok := entities.GetComponent[*components.Room](p.CurrentRoom)
+	if !ok {
+		return
+	}
+	door, ok := room.GetExit(direction)
+	if !ok || !door.Door || !door.Closed || door.Locked {
+		return
+	}
+
+	w.PublishTo(p.CurrentRoom, p.Entity, w.openDoor(p, door))
+}
@@ -0,0 +1,24 @@
+package world_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"example.com/mud/world/worldtest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorld_Travel(t *testing.T) {
+	dir := filepath.Join("testdata", "travel")
+
+	w, err := worldtest.FromDirectory(dir, "Gate")
+	require.NoError(t, err)
+	worldtest.RunTranscript(t, w, filepath.Join(dir, "journey.txt"))
+
+	// each step leaves her catching her breath, as if she had typed it
+	alice, ok := w.Player("Alice")
+	require.True(t, ok)
+	require.Positive(t, alice.CooldownRemaining())
+
+	worldtest.RunTranscript(t, w, filepath.Join(dir, "detours.txt"))
+}
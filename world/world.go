@@ -1,10 +1,13 @@
 package world
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
+	"example.com/mud/models"
 	"example.com/mud/parser"
 	"example.com/mud/parser/commands"
 	"example.com/mud/world/entities"
@@ -16,31 +19,104 @@ import (
 type World struct {
 	Scheduler *scheduler.Scheduler
 
+	// names of players allowed to use admin commands
+	Admins []string
+	// where the save command writes snapshots, left empty to disable it
+	SnapshotPath string
+	// where the reload command reads definitions from
+	DataDirectory string
+	// what happens to restored jobs which came due while the world was down
+	MissedJobs MissedJobs
+	// how long players travelling wait between steps
+	TravelStep time.Duration
+	// maps show every room, not only those players have explored
+	RevealMap bool
+
 	entityMap    map[string]*entities.Entity
 	startingRoom string
 	bus          *Bus
+
+	// connected players by lowercase name
+	players map[string]*player.Player
+
+	// everything changing the world is run in turn from here, see Do
+	queue chan func()
+	// set once the world stops taking commands and players
+	closed bool
+	// how many commands performed, or moves made, by entities are running
+	// inside one another
+	performing int
 }
 
+var (
+	ErrPlayerConnected = errors.New("player is already connected")
+	ErrShuttingDown    = errors.New("world is shutting down")
+)
+
 func NewWorld(entityMap map[string]*entities.Entity, startingRoom string) *World {
-	return &World{
+	return NewWorldWithScheduler(entityMap, startingRoom, scheduler.NewScheduler())
+}
+
+// NewWorldWithScheduler creates a world running its jobs on s, e.g. one on a
+// fake clock so tests can control time.
+func NewWorldWithScheduler(entityMap map[string]*entities.Entity, startingRoom string, s *scheduler.Scheduler) *World {
+	w := &World{
 		entityMap:    entityMap,
 		startingRoom: startingRoom,
-		Scheduler:    scheduler.NewScheduler(),
+		Scheduler:    s,
 		bus:          NewBus(),
+		players:      make(map[string]*player.Player),
+		queue:        make(chan func()),
 	}
+	s.SetRunner(w.runJob)
+
+	go w.run()
+	w.Do(w.startAllBehaviors)
+	return w
 }
 
 func (w *World) EntitiesById() map[string]*entities.Entity { return w.entityMap }
 
-func (w *World) AddPlayer(name string, inbox chan string) (*player.Player, error) {
+func (w *World) MapRevealed() bool { return w.RevealMap }
+
+// AddPlayer places a player in the world, resuming from saved if it is not nil.
+// Only one player of the same name may be connected at a time.
+func (w *World) AddPlayer(name string, inbox chan string, data chan models.OutOfBand, saved *player.State) (*player.Player, error) {
+	var p *player.Player
+	var err error
+	w.Do(func() {
+		p, err = w.addPlayer(name, inbox, data, saved)
+	})
+	return p, err
+}
+
+func (w *World) addPlayer(name string, inbox chan string, data chan models.OutOfBand, saved *player.State) (*player.Player, error) {
 	startingRoom, ok := w.entityMap[w.startingRoom]
 	if !ok {
 		log.Fatalf("add player: room '%s' does not exist in world.", w.startingRoom)
 	}
 
-	newPlayer, err := player.NewPlayer(name, w, startingRoom)
+	// players resume in the room they left, unless it has since been removed
+	if saved != nil {
+		if room, ok := w.entityMap[saved.Room]; ok {
+			if _, ok := entities.GetComponent[*components.Room](room); ok {
+				startingRoom = room
+			}
+		}
+	}
+
+	if w.closed {
+		return nil, fmt.Errorf("could not add player '%s': %w", name, ErrShuttingDown)
+	}
+
+	key := strings.ToLower(name)
+	if _, ok := w.players[key]; ok {
+		return nil, fmt.Errorf("could not add player '%s': %w", name, ErrPlayerConnected)
+	}
+
+	newPlayer, err := w.newPlayer(name, startingRoom, data, saved)
 	if err != nil {
-		return nil, fmt.Errorf("could not create player '%s': %w", name, err)
+		return nil, err
 	}
 
 	if room, ok := entities.GetComponent[*components.Room](newPlayer.CurrentRoom); ok {
@@ -50,16 +126,106 @@ func (w *World) AddPlayer(name string, inbox chan string) (*player.Player, error
 	w.bus.Subscribe(newPlayer.CurrentRoom, newPlayer.Entity, inbox)
 	w.Publish(newPlayer.CurrentRoom, fmt.Sprintf("%s enters the room.", newPlayer.Name), []*entities.Entity{newPlayer.Entity})
 
+	w.players[key] = newPlayer
+	w.roomEvent(newPlayer.CurrentRoom, newPlayer.Entity, entities.EventConnect, "")
+	w.roomEvent(newPlayer.CurrentRoom, newPlayer.Entity, entities.EventEnter, "")
+
+	w.SyncOutOfBand()
+
 	return newPlayer, nil
 }
 
+func (w *World) newPlayer(name string, room *entities.Entity, data chan models.OutOfBand, saved *player.State) (*player.Player, error) {
+	newPlayer, err := player.NewPlayer(name, w, room, data)
+	if err != nil {
+		return nil, fmt.Errorf("could not create player '%s': %w", name, err)
+	}
+
+	if saved != nil && saved.Entity != nil {
+		if err := newPlayer.Restore(saved.Entity); err != nil {
+			return nil, fmt.Errorf("could not create player '%s': %w", name, err)
+		}
+	}
+	if saved != nil {
+		newPlayer.RestoreExplored(saved.Explored)
+	}
+
+	return newPlayer, nil
+}
+
+// DisconnectPlayer removes a player from the world, it is safe to call more than once.
 func (w *World) DisconnectPlayer(p *player.Player) {
+	w.Do(func() {
+		w.disconnectPlayer(p)
+	})
+}
+
+func (w *World) disconnectPlayer(p *player.Player) {
+	key := strings.ToLower(p.Name)
+	if w.players[key] != p {
+		return
+	}
+	delete(w.players, key)
+
 	if room, ok := entities.GetComponent[*components.Room](p.CurrentRoom); ok {
 		room.RemoveChild(p.Entity)
 	}
 
 	w.bus.Unsubscribe(p.CurrentRoom, p.Entity)
 	w.Publish(p.CurrentRoom, fmt.Sprintf("%s leaves the room.", p.Name), []*entities.Entity{p.Entity})
+	w.roomEvent(p.CurrentRoom, p.Entity, entities.EventLeave, "")
+	w.roomEvent(p.CurrentRoom, p.Entity, entities.EventDisconnect, "")
+
+	// anything the player set going stops with them
+	w.Scheduler.CancelOwnedBy(p.Entity, "")
+
+	w.SyncOutOfBand()
+
+	p.Disconnect()
+}
+
+// DisconnectAll disconnects every player in the world.
+func (w *World) DisconnectAll() {
+	w.Do(func() {
+		for _, p := range w.players {
+			w.disconnectPlayer(p)
+		}
+	})
+}
+
+// OpeningMessage describes where p is, for when they first connect.
+func (w *World) OpeningMessage(p *player.Player) (string, error) {
+	var message string
+	var err error
+	w.Do(func() {
+		message, err = p.OpeningMessage()
+	})
+	return message, err
+}
+
+// PlayerState captures what is saved of p, such as its inventory and fields.
+func (w *World) PlayerState(p *player.Player) *player.State {
+	var state *player.State
+	w.Do(func() {
+		state = p.State()
+	})
+	return state
+}
+
+// Broadcast sends text to every connected player.
+func (w *World) Broadcast(text string) {
+	w.bus.Broadcast(text)
+}
+
+// Shutdown stops the world from accepting new commands and players, and waits
+// for commands and scheduled jobs already running to finish. Players can still
+// be disconnected and saved afterwards.
+func (w *World) Shutdown() {
+	w.Do(func() {
+		w.closed = true
+	})
+
+	w.Scheduler.Stop()
 }
 
 func (w *World) GetEntityById(id string) (*entities.Entity, bool) {
@@ -75,13 +241,34 @@ func (w *World) PublishTo(room *entities.Entity, recipient *entities.Entity, tex
 	w.bus.PublishTo(room, recipient, text)
 }
 
-func (w *World) GetScheduler() *scheduler.Scheduler {
+func (w *World) GetScheduler() entities.Scheduler {
 	return w.Scheduler
 }
 
+// Parse runs a line typed by p, returning the reply.
 func (w *World) Parse(p *player.Player, line string) (string, error) {
+	var reply string
+	var err error
+	w.Do(func() {
+		reply, err = w.parse(p, line)
+	})
+	return reply, err
+}
+
+func (w *World) parse(p *player.Player, line string) (string, error) {
+	if w.closed {
+		return "The world is ending.", nil
+	}
+
+	defer w.SyncOutOfBand()
+
 	cmd := parser.Parse(line)
 	if cmd == nil {
+		// exits going by a name rather than a direction are walked by typing
+		// it, e.g. "enter wardrobe", unless it means a command
+		if exit, ok := w.namedExit(p, line); ok {
+			return p.Move(exit.Direction)
+		}
 		return "What in the nine hells?", nil
 	}
 
@@ -97,11 +284,24 @@ func (w *World) Parse(p *player.Player, line string) (string, error) {
 	case "map":
 		return p.Map()
 	case "track":
-		return p.Track(cmd.Params["target"])
+		return w.trackCommand(p, cmd.Params["target"])
+	case "travel":
+		return w.travelCommand(p, cmd.Params["destination"])
+	case "save":
+		return w.saveCommand(p)
+	case "reload":
+		return w.reloadCommand(p)
+	case "jobs":
+		return w.jobsCommand(p)
 	}
 
 	// see if it has target
 	if target := cmd.Params["target"]; target != "" {
+		// doors aren't entities, but answer to the same commands
+		if reply, ok, err := w.doorCommand(p, cmd.Kind, target, cmd.Params["instrument"]); ok {
+			return reply, err
+		}
+
 		if instrument := cmd.Params["instrument"]; instrument != "" {
 			response, err := p.ActUponWithAlias(cmd.Kind, target, instrument, cmd.NoMatchMessage)
 			return response, err
@@ -123,6 +323,76 @@ func (w *World) Parse(p *player.Player, line string) (string, error) {
 	return "What the hell are you talking about?", nil
 }
 
+// ResolveAmbiguity runs an action once the player has chosen between the
+// entities that matched their command.
+func (w *World) ResolveAmbiguity(amb *entities.AmbiguityError, chosen map[string]*entities.Entity) (string, error) {
+	var reply string
+	var err error
+	w.Do(func() {
+		if w.closed {
+			reply = "The world is ending."
+			return
+		}
+
+		defer w.SyncOutOfBand()
+		reply, err = amb.Execute(chosen)
+	})
+	return reply, err
+}
+
+// IsAdmin reports whether the player may use admin commands. Entities
+// performing commands never may.
+func (w *World) IsAdmin(p *player.Player) bool {
+	if w.players[strings.ToLower(p.Name)] != p {
+		return false
+	}
+
+	for _, name := range w.Admins {
+		if strings.EqualFold(name, p.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *World) saveCommand(p *player.Player) (string, error) {
+	if !w.IsAdmin(p) {
+		return "Only the gods may do that.", nil
+	}
+	if w.SnapshotPath == "" {
+		return "Saving is not configured for this world.", nil
+	}
+
+	// already on the loop, so the snapshot is taken here rather than by SaveSnapshot
+	if err := writeSnapshot(w.SnapshotPath, w.Snapshot()); err != nil {
+		return "", fmt.Errorf("save command for player '%s': %w", p.Name, err)
+	}
+
+	return "The world has been saved.", nil
+}
+
+func (w *World) reloadCommand(p *player.Player) (string, error) {
+	if !w.IsAdmin(p) {
+		return "Only the gods may do that.", nil
+	}
+
+	// reload is queued up behind this command, so it reports back once it is done
+	go func() {
+		result, err := w.Reload()
+
+		w.Do(func() {
+			if err != nil {
+				w.PublishTo(p.CurrentRoom, p.Entity, fmt.Sprintf("Reload failed, the world is unchanged:\n%v", err))
+				return
+			}
+
+			w.PublishTo(p.CurrentRoom, p.Entity, fmt.Sprintf("The world has been reloaded: %s", result))
+		})
+	}()
+
+	return "Reloading the world...", nil
+}
+
 func (w *World) HelpMessage(command string) string {
 	if command == "" {
 		return w.HelpGeneral()
@@ -171,35 +441,16 @@ func (w *World) HelpGeneral() string {
 }
 
 func (w *World) MovePlayer(p *player.Player, direction string) (string, error) {
-	playerRoom, err := entities.RequireComponent[*components.Room](p.CurrentRoom)
-	if err != nil {
-		return "", fmt.Errorf("move for player '%s': %w", p.Name, err)
+	newRoom, refusal, err := w.throughExit(p.Entity, p.CurrentRoom, direction)
+	if err != nil || newRoom == nil {
+		return refusal, err
 	}
 
-	newRoom := w.getNeighboringRoom(playerRoom, direction)
-	if newRoom != nil {
-		w.Publish(p.CurrentRoom, fmt.Sprintf("%s leaves the room.", p.Name), []*entities.Entity{p.Entity})
-
-		playerRoom.RemoveChild(p.Entity)
+	w.relocate(p.Entity, p.CurrentRoom, newRoom, direction, func() {
 		p.CurrentRoom = newRoom
+		p.Explore(newRoom)
+		w.bus.Move(newRoom, p.Entity)
+	})
 
-		if room, ok := entities.GetComponent[*components.Room](p.CurrentRoom); ok {
-			room.AddChild(p.Entity)
-		}
-
-		w.bus.Move(p.CurrentRoom, p.Entity)
-		w.Publish(p.CurrentRoom, fmt.Sprintf("%s enters the room.", p.Name), []*entities.Entity{p.Entity})
-
-		return p.GetRoomDescription()
-	}
-
-	return "You can't go there.", nil
-}
-
-func (w *World) getNeighboringRoom(r *components.Room, direction string) *entities.Entity {
-	if roomId, ok := r.GetNeighboringRoomId(direction); ok {
-		room := w.entityMap[roomId]
-		return room
-	}
-	return nil
+	return p.GetRoomDescription()
 }
@@ -0,0 +1,268 @@
+package worldtest
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// UpdateEnv names the environment variable which, when set, makes
+// RunTranscript rewrite transcripts with the output they actually produced
+// instead of failing.
+const UpdateEnv = "WORLDTEST_UPDATE"
+
+// a player typing a command, "Alice> look", or "> look" for the first player
+var transcriptInput = regexp.MustCompile(`^(\w*)> ?(.*)$`)
+
+// a directive like "@advance 5s", which maps drawing the player as "@" aren't
+var transcriptDirective = regexp.MustCompile(`^@\w+(\s|$)`)
+
+// RunTranscripts plays every transcript in directory, each against a new
+// world built from the definitions there, with players joining in
+// startingRoom.
+func RunTranscripts(t *testing.T, directory string, startingRoom string) {
+	t.Helper()
+
+	paths, err := filepath.Glob(filepath.Join(directory, "*.txt"))
+	if err != nil {
+		t.Fatalf("find transcripts: %v", err)
+	}
+	if len(paths) == 0 {
+		t.Fatalf("no transcripts in %s", directory)
+	}
+
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			w, err := FromDirectory(directory, startingRoom)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			RunTranscript(t, w, path)
+		})
+	}
+}
+
+// a step is one line of input, and the output expected to follow it
+type transcriptStep struct {
+	line     int
+	comments []string
+	input    string
+	expected []string
+}
+
+// RunTranscript plays the transcript at path against w, failing t wherever
+// the output differs from what the transcript expects.
+//
+// Transcripts are plain text. Lines starting with "#" are comments, and
+// lines like "Alice> take lamp" are commands typed by a player, "> take lamp"
+// being the first player to join. These directives control the world:
+//
+//	@join Alice      Alice connects
+//	@leave Alice     Alice disconnects
+//	@advance 5s      time moves forward, running scheduled jobs
+//
+// Every other line is expected output. A command's reply, and anything else
+// its player was sent, is written as is. Messages to other players are
+// written as "[Bob] Alice takes the lamp.". Color codes and blank lines are
+// ignored.
+func RunTranscript(t testing.TB, w *World, path string) {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read transcript: %v", err)
+	}
+
+	steps, trailing := parseTranscript(data)
+
+	actual := make([][]string, len(steps))
+	failed := false
+	for i, step := range steps {
+		out, err := w.playStep(step.input)
+		if err != nil {
+			t.Fatalf("%s:%d: %v", path, step.line, err)
+		}
+		actual[i] = out
+
+		if !equalLines(out, step.expected) {
+			failed = true
+			if os.Getenv(UpdateEnv) == "" {
+				t.Errorf("%s:%d: %s\ngot:\n%s\nwant:\n%s", path, step.line, step.input,
+					indent(out), indent(step.expected))
+			}
+		}
+	}
+
+	if failed && os.Getenv(UpdateEnv) != "" {
+		if err := os.WriteFile(path, renderTranscript(steps, actual, trailing), 0o644); err != nil {
+			t.Fatalf("update transcript: %v", err)
+		}
+		t.Logf("updated %s", path)
+	}
+}
+
+func parseTranscript(data []byte) ([]*transcriptStep, []string) {
+	var steps []*transcriptStep
+	var comments []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "#"):
+			comments = append(comments, line)
+		case transcriptDirective.MatchString(line) || transcriptInput.MatchString(line):
+			steps = append(steps, &transcriptStep{
+				line:     n,
+				comments: comments,
+				input:    line,
+			})
+			comments = nil
+		case len(steps) > 0:
+			last := steps[len(steps)-1]
+			last.expected = append(last.expected, line)
+		}
+	}
+
+	return steps, comments
+}
+
+func renderTranscript(steps []*transcriptStep, actual [][]string, trailing []string) []byte {
+	var b bytes.Buffer
+	for i, step := range steps {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		for _, c := range step.comments {
+			b.WriteString(c + "\n")
+		}
+		b.WriteString(step.input + "\n")
+		for _, line := range actual[i] {
+			b.WriteString(line + "\n")
+		}
+	}
+	if len(trailing) > 0 {
+		b.WriteString("\n" + strings.Join(trailing, "\n") + "\n")
+	}
+	return b.Bytes()
+}
+
+// run a single line of a transcript, returning the output it produced
+func (w *World) playStep(input string) ([]string, error) {
+	var acting *Player
+	var out []string
+
+	if transcriptDirective.MatchString(input) {
+		directive, arg, _ := strings.Cut(strings.TrimPrefix(input, "@"), " ")
+		arg = strings.TrimSpace(arg)
+
+		switch directive {
+		case "join":
+			p, err := w.Join(arg)
+			if err != nil {
+				return nil, err
+			}
+			message, err := w.OpeningMessage(p.Player)
+			if err != nil {
+				return nil, err
+			}
+			acting = p
+			out = append(out, splitLines(message)...)
+		case "leave":
+			p, ok := w.Player(arg)
+			if !ok {
+				return nil, fmt.Errorf("no player named '%s'", arg)
+			}
+			w.Leave(p)
+		case "advance":
+			d, err := time.ParseDuration(arg)
+			if err != nil {
+				return nil, fmt.Errorf("advance: %w", err)
+			}
+			w.Advance(d)
+		default:
+			return nil, fmt.Errorf("unknown directive '@%s'", directive)
+		}
+	} else {
+		m := transcriptInput.FindStringSubmatch(input)
+		name, line := m[1], m[2]
+
+		if name == "" {
+			if len(w.players) == 0 {
+				return nil, fmt.Errorf("no players have joined")
+			}
+			acting = w.players[0]
+		} else {
+			p, ok := w.Player(name)
+			if !ok {
+				return nil, fmt.Errorf("no player named '%s'", name)
+			}
+			acting = p
+		}
+
+		reply, err := acting.Do(line)
+		if err != nil {
+			// errors are shown to players, so they're part of the transcript
+			reply = fmt.Sprintf("error received: %v", err)
+		}
+		out = append(out, splitLines(reply)...)
+	}
+
+	if acting != nil {
+		for _, msg := range acting.Messages() {
+			out = append(out, splitLines(msg)...)
+		}
+	}
+	for _, p := range w.players {
+		if p == acting {
+			continue
+		}
+		for _, msg := range p.Messages() {
+			for _, line := range splitLines(msg) {
+				out = append(out, fmt.Sprintf("[%s] %s", p.Name, line))
+			}
+		}
+	}
+
+	return out, nil
+}
+
+func splitLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(Plain(text), "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func indent(lines []string) string {
+	if len(lines) == 0 {
+		return "  (nothing)"
+	}
+	return "  " + strings.Join(lines, "\n  ")
+}
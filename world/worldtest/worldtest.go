@@ -0,0 +1,256 @@
+// Package worldtest runs a world headlessly for tests, with fake players
+// typing commands and a scheduler that only moves when told to.
+package worldtest
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"example.com/mud/dsl"
+	"example.com/mud/models"
+	"example.com/mud/parser/commands"
+	"example.com/mud/utils"
+	"example.com/mud/world"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/components"
+	"example.com/mud/world/player"
+	"example.com/mud/world/scheduler"
+)
+
+// Start is the time every test world's scheduler begins at.
+var Start = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// World is a game world without any network, driven by Players and Advance.
+//
+// Commands are registered globally, like they are for the server, so tests
+// using worlds with different commands shouldn't run in parallel.
+type World struct {
+	*world.World
+
+	// the world's time, which only moves with Advance
+	Clock *scheduler.FakeClock
+
+	// players in the order they joined
+	players []*Player
+}
+
+// FromDirectory builds a world from every definition file in directory.
+func FromDirectory(directory string, startingRoom string) (*World, error) {
+	entityMap, cmds, err := dsl.LoadEntitiesFromDirectory(directory)
+	if err != nil {
+		return nil, fmt.Errorf("load %s: %w", directory, err)
+	}
+	return New(entityMap, cmds, startingRoom)
+}
+
+// FromString builds a world from definitions held in source.
+func FromString(source string, startingRoom string) (*World, error) {
+	entityMap, cmds, err := dsl.LoadEntitiesFromString("worldtest.mud", source)
+	if err != nil {
+		return nil, fmt.Errorf("load definitions: %w", err)
+	}
+	return New(entityMap, cmds, startingRoom)
+}
+
+// New builds a world from entities and commands that were already compiled.
+func New(entityMap map[string]*entities.Entity, cmds []*models.CommandDefinition, startingRoom string) (*World, error) {
+	if _, ok := entityMap[startingRoom]; !ok {
+		return nil, fmt.Errorf("room '%s' does not exist in world", startingRoom)
+	}
+
+	if err := commands.ReplaceCommands(cmds); err != nil {
+		return nil, fmt.Errorf("register commands: %w", err)
+	}
+
+	clock := scheduler.NewFakeClock(Start)
+	return &World{
+		World: world.NewWorldWithScheduler(entityMap, startingRoom, scheduler.NewSchedulerWithClock(clock)),
+		Clock: clock,
+	}, nil
+}
+
+// Advance moves the world's time forward by d, running every scheduled job
+// that comes due.
+func (w *World) Advance(d time.Duration) {
+	w.Clock.Advance(d)
+}
+
+// Now is the world's current time.
+func (w *World) Now() time.Time {
+	return w.Clock.Now()
+}
+
+// Entity finds an entity by the id it was defined with.
+func (w *World) Entity(id string) (*entities.Entity, bool) {
+	return w.GetEntityById(id)
+}
+
+// Find looks for an entity in a room by alias, as a player standing there
+// would. Entities in rooms are copies of their definitions, so this, not
+// Entity, finds the one players are acting upon.
+func (w *World) Find(roomId string, alias string) (*entities.Entity, bool) {
+	room, ok := w.GetEntityById(roomId)
+	if !ok {
+		return nil, false
+	}
+
+	rm, ok := entities.GetComponent[*components.Room](room)
+	if !ok {
+		return nil, false
+	}
+
+	matches := rm.GetChildren().GetChildrenByAlias(alias)
+	if len(matches) == 0 {
+		return nil, false
+	}
+	return matches[0].Entity, true
+}
+
+// Join connects a new player named name, placed in the starting room.
+func (w *World) Join(name string) (*Player, error) {
+	p := &Player{
+		world: w,
+		// large enough that nothing is dropped between reads
+		inbox: make(chan string, 1024),
+		data:  make(chan models.OutOfBand, 1024),
+	}
+
+	var err error
+	p.Player, err = w.AddPlayer(name, p.inbox, p.data, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	w.players = append(w.players, p)
+	return p, nil
+}
+
+// Player finds a player who joined by name.
+func (w *World) Player(name string) (*Player, bool) {
+	for _, p := range w.players {
+		if strings.EqualFold(p.Name, name) {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// Players returns every player still connected, in the order they joined.
+func (w *World) Players() []*Player {
+	return w.players
+}
+
+// Leave disconnects p from the world.
+func (w *World) Leave(p *Player) {
+	w.DisconnectPlayer(p.Player)
+	for i, other := range w.players {
+		if other == p {
+			w.players = append(w.players[:i], w.players[i+1:]...)
+			break
+		}
+	}
+}
+
+// Player is a fake connection, capturing everything sent to it.
+type Player struct {
+	*player.Player
+
+	world *World
+	inbox chan string
+	data  chan models.OutOfBand
+}
+
+// Do types line as the player, returning the reply a client would show.
+// Ambiguous commands reply with the choices, and a following number picks
+// one, just like over telnet.
+func (p *Player) Do(line string) (string, error) {
+	line = strings.TrimSpace(line)
+
+	if pending := p.Pending; pending != nil {
+		if n, err := strconv.Atoi(line); err == nil {
+			return p.choose(pending, n)
+		}
+		p.Pending = nil
+	}
+
+	reply, err := p.world.Parse(p.Player, line)
+	var amb *entities.AmbiguityError
+	if errors.As(err, &amb) {
+		p.Pending = &entities.PendingAction{
+			Ambiguity: amb,
+			Selected:  map[string]int{},
+		}
+		return choices(p.Pending), nil
+	}
+
+	return reply, err
+}
+
+func (p *Player) choose(pending *entities.PendingAction, n int) (string, error) {
+	slot := pending.Ambiguity.Slots[pending.StepIndex]
+	if n < 1 || n > len(slot.Matches) {
+		return "", nil
+	}
+
+	pending.Selected[slot.Role] = n - 1
+	pending.StepIndex++
+	if pending.StepIndex < len(pending.Ambiguity.Slots) {
+		return choices(pending), nil
+	}
+
+	chosen := make(map[string]*entities.Entity, len(pending.Selected))
+	for _, s := range pending.Ambiguity.Slots {
+		chosen[s.Role] = s.Matches[pending.Selected[s.Role]].Entity
+	}
+
+	p.Pending = nil
+	return p.world.ResolveAmbiguity(pending.Ambiguity, chosen)
+}
+
+func choices(pending *entities.PendingAction) string {
+	slot := pending.Ambiguity.Slots[pending.StepIndex]
+
+	var b strings.Builder
+	b.WriteString(slot.Prompt)
+	for i, opt := range slot.Matches {
+		fmt.Fprintf(&b, "\n  %d) %s", i+1, opt.Text)
+	}
+	return b.String()
+}
+
+// Messages returns everything published to the player since it was last
+// called, such as other players acting, or scheduled reactions.
+func (p *Player) Messages() []string {
+	var messages []string
+	for {
+		select {
+		case msg := <-p.inbox:
+			messages = append(messages, msg)
+		default:
+			return messages
+		}
+	}
+}
+
+// Data returns the out-of-band data sent to the player since it was last called.
+func (p *Player) Data() []models.OutOfBand {
+	var data []models.OutOfBand
+	for {
+		select {
+		case msg := <-p.data:
+			data = append(data, msg)
+			// like a connection, making room for packages that didn't fit
+			p.FlushOutOfBand()
+		default:
+			return data
+		}
+	}
+}
+
+// Plain strips color codes from text, for comparing against expected output.
+func Plain(text string) string {
+	return utils.StripANSI(text)
+}
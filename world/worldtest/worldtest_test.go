@@ -0,0 +1,269 @@
+// worldtest/worldtest_test.go
+package worldtest
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"example.com/mud/models"
+	"github.com/stretchr/testify/require"
+)
+
+// commands are registered globally, so these tests don't run in parallel
+
+func TestWorld_Play(t *testing.T) {
+	w, err := FromDirectory("testdata", "Hall")
+	require.NoError(t, err)
+
+	alice, err := w.Join("Alice")
+	require.NoError(t, err)
+	bob, err := w.Join("Bob")
+	require.NoError(t, err)
+	require.Equal(t, []string{"Bob enters the room."}, alice.Messages())
+
+	// reactions print to the player rather than replying
+	reply, err := alice.Do("light candle")
+	require.NoError(t, err)
+	require.Empty(t, reply)
+	require.Equal(t, []string{"You light the candle."}, alice.Messages())
+	require.Equal(t, []string{"Alice lights the candle."}, bob.Messages())
+
+	candle, ok := w.Find("Hall", "candle")
+	require.True(t, ok)
+	lit := func() bool {
+		return candle.Fields["lit"].B
+	}
+	require.True(t, lit())
+
+	w.Advance(4 * time.Second)
+	require.True(t, lit())
+	require.Empty(t, bob.Messages())
+
+	w.Advance(time.Second)
+	require.False(t, lit())
+	require.Equal(t, []string{"The candle gutters out."}, bob.Messages())
+	require.Equal(t, worldStart(5*time.Second), w.Now())
+}
+
+func TestWorld_FromString(t *testing.T) {
+	w, err := FromString(`
+entity Player {
+    name is "Player"
+    description is "Player Template"
+    aliases is ["player"]
+}
+
+entity Cell {
+    name is "Cell"
+    description is "Bare stone walls."
+    aliases is ["cell"]
+
+    component Room {
+    }
+}
+`, "Cell")
+	require.NoError(t, err)
+
+	p, err := w.Join("Alice")
+	require.NoError(t, err)
+
+	reply, err := p.Do("look")
+	require.NoError(t, err)
+	require.Contains(t, Plain(reply), "Bare stone walls.")
+
+	var roomInfo *models.OutOfBand
+	for _, d := range p.Data() {
+		if d.Package == "Room.Info" {
+			roomInfo = &d
+		}
+	}
+	require.NotNil(t, roomInfo)
+	require.Contains(t, string(roomInfo.Data), `"Cell"`)
+
+	_, err = FromString(`entity Broken {`, "Cell")
+	require.ErrorContains(t, err, "worldtest.mud:1:")
+}
+
+func TestWorld_CancelJobs(t *testing.T) {
+	w, err := FromString(`
+entity Player {
+    name is "Player"
+    description is "Player Template"
+    aliases is ["player"]
+}
+
+entity Cell {
+    name is "Cell"
+    description is "Bare stone walls."
+    aliases is ["cell"]
+
+    component Room {
+        children is [
+            "Drip",
+            "Rat"
+        ]
+    }
+}
+
+entity Drip {
+    name is "Drip"
+    description is "Water drips from the ceiling."
+    aliases is ["drip"]
+
+    react poke {
+        then {
+            repeat every 1 second as "drip" while {
+            } then {
+                publish "Drip."
+            }
+        }
+    }
+
+    react plug {
+        then {
+            cancel target "drip"
+            publish "The dripping stops."
+        }
+    }
+}
+
+entity Rat {
+    name is "Rat"
+    description is "A scrawny rat."
+    aliases is ["rat"]
+
+    react poke {
+        then {
+            in 2 seconds {
+                publish "The rat squeaks."
+            }
+        }
+    }
+
+    react stomp {
+        then {
+            destroy target
+        }
+    }
+}
+
+command Poke {
+    aliases is ["poke"]
+
+    pattern {
+        syntax is "poke {target}"
+        noMatch is "You can't poke that."
+    }
+}
+
+command Plug {
+    aliases is ["plug"]
+
+    pattern {
+        syntax is "plug {target}"
+        noMatch is "You can't plug that."
+    }
+}
+
+command Stomp {
+    aliases is ["stomp"]
+
+    pattern {
+        syntax is "stomp {target}"
+        noMatch is "You can't stomp that."
+    }
+}
+`, "Cell")
+	require.NoError(t, err)
+	w.Admins = []string{"Alice"}
+
+	alice, err := w.Join("Alice")
+	require.NoError(t, err)
+	bob, err := w.Join("Bob")
+	require.NoError(t, err)
+
+	for _, line := range []string{"poke drip", "poke rat"} {
+		_, err = alice.Do(line)
+		require.NoError(t, err)
+	}
+
+	reply, err := bob.Do("jobs")
+	require.NoError(t, err)
+	require.Equal(t, "Only the gods may do that.", reply)
+
+	reply, err = alice.Do("jobs")
+	require.NoError(t, err)
+	require.Equal(t, "2 jobs waiting:\n  #1 Drip: drip (in 1s)\n  #2 Rat: in 2 seconds (in 2s)", reply)
+
+	w.Advance(time.Second)
+	require.Equal(t, []string{"Drip."}, bob.Messages())
+
+	// destroying the rat cancels its squeak
+	_, err = alice.Do("stomp rat")
+	require.NoError(t, err)
+	_, err = alice.Do("plug drip")
+	require.NoError(t, err)
+	require.Equal(t, []string{"The dripping stops."}, bob.Messages())
+
+	w.Advance(time.Minute)
+	require.Empty(t, bob.Messages())
+
+	reply, err = alice.Do("jobs")
+	require.NoError(t, err)
+	require.Equal(t, "Nothing is waiting to happen.", reply)
+}
+
+func TestTranscripts(t *testing.T) {
+	RunTranscripts(t, "testdata", "Hall")
+}
+
+func worldStart(d time.Duration) time.Time {
+	return Start.Add(d)
+}
+
+// run with -race, players and scheduled jobs all change the world at once
+func TestWorld_ConcurrentPlayers(t *testing.T) {
+	w, err := FromDirectory("testdata", "Hall")
+	require.NoError(t, err)
+
+	names := []string{"Alice", "Bob", "Carol", "Dave"}
+	players := make([]*Player, len(names))
+	for i, name := range names {
+		players[i], err = w.Join(name)
+		require.NoError(t, err)
+	}
+
+	var wg sync.WaitGroup
+	for _, p := range players {
+		wg.Add(1)
+		go func(p *Player) {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				for _, line := range []string{"light candle", "look", "north", "look", "south", "map"} {
+					_, err := p.Do(line)
+					require.NoError(t, err)
+				}
+				p.Messages()
+				p.Data()
+			}
+		}(p)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			w.Advance(time.Second)
+		}
+	}()
+
+	wg.Wait()
+
+	// everyone ended up back in the hall
+	for _, p := range players {
+		reply, err := p.Do("look")
+		require.NoError(t, err)
+		require.Contains(t, Plain(reply), "A long, drafty hall.")
+	}
+}